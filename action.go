@@ -0,0 +1,115 @@
+package qui
+
+// Action is a command that can be shown by any number of buttons, tool buttons
+// and menu items and triggered by a shortcut. Widgets bound to an action take
+// their text, icon, tooltip, shortcut, disabled and checked state from it when
+// they are bound and whenever it changes through one of its setters. After
+// setting fields directly, call Update to show the changes.
+type Action struct {
+	Text    string
+	Icon    Icon
+	Tooltip string
+	// Shortcut is shown on bound menu items. Use Master.BindAction to make it
+	// trigger the action.
+	Shortcut string
+	Disabled bool
+	// Checkable actions toggle Checked each time they are triggered.
+	Checkable bool
+	Checked   bool
+	// Handler is called when the action is triggered.
+	Handler func()
+
+	views []actionView
+}
+
+// actionView is a widget bound to an Action.
+type actionView interface {
+	// sync copies the state of the bound action.
+	sync()
+}
+
+func NewAction(text string, icon Icon, handler func()) *Action {
+	return &Action{
+		Text:    text,
+		Icon:    icon,
+		Handler: handler,
+	}
+}
+
+// Trigger performs the action unless it is disabled.
+func (a *Action) Trigger() {
+	if a.Disabled {
+		return
+	}
+	if a.Checkable {
+		a.SetChecked(!a.Checked)
+	}
+	if a.Handler != nil {
+		a.Handler()
+	}
+}
+
+// Update shows the current state of a on all widgets bound to it.
+func (a *Action) Update() {
+	for _, v := range a.views {
+		v.sync()
+	}
+}
+
+func (a *Action) SetText(text string) {
+	a.Text = text
+	a.Update()
+}
+
+func (a *Action) SetIcon(icon Icon) {
+	a.Icon = icon
+	a.Update()
+}
+
+func (a *Action) SetTooltip(tooltip string) {
+	a.Tooltip = tooltip
+	a.Update()
+}
+
+func (a *Action) SetShortcut(shortcut string) {
+	a.Shortcut = shortcut
+	a.Update()
+}
+
+func (a *Action) SetDisabled(disabled bool) {
+	a.Disabled = disabled
+	a.Update()
+}
+
+func (a *Action) SetChecked(checked bool) {
+	a.Checked = checked
+	a.Update()
+}
+
+// bind adds v to the widgets showing a.
+func (a *Action) bind(v actionView) {
+	a.views = append(a.views, v)
+	v.sync()
+}
+
+// unbind removes v from the widgets showing a.
+func (a *Action) unbind(v actionView) {
+	for i, o := range a.views {
+		if o == v {
+			a.views = append(a.views[:i], a.views[i+1:]...)
+			return
+		}
+	}
+}
+
+// BindAction binds a's shortcut to trigger it while scope is active, see
+// BindShortcut. The shortcut text of a is normalized, e.g. "ctrl+s" becomes
+// "Ctrl+S".
+func (m *Master) BindAction(a *Action, scope Widget) (*ShortcutBinding, error) {
+	b, err := m.BindShortcut(a.Shortcut, scope, a.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	a.SetShortcut(b.Shortcut.String())
+	return b, nil
+}
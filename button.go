@@ -10,9 +10,14 @@ type Button struct {
 	Text    string
 	Icon    Icon
 	OnClick func()
+	// Checked buttons are drawn pressed in, as used by toggle tool buttons.
+	Checked bool
 
 	hovered bool
 	pressed bool
+	focused bool
+	flat    bool // Tool buttons only draw a background when hovered or checked
+	action  *Action
 }
 
 func NewButton(text string, onClick func()) *Button {
@@ -22,6 +27,57 @@ func NewButton(text string, onClick func()) *Button {
 	}
 }
 
+// NewActionButton creates a button bound to a, see BindAction.
+func NewActionButton(a *Action) *Button {
+	b := &Button{}
+	b.BindAction(a)
+	return b
+}
+
+// NewToolButton creates a flat button for toolbars that shows only the icon
+// of a, with its text as the tooltip if it has no tooltip of its own.
+func NewToolButton(a *Action) *Button {
+	b := &Button{flat: true}
+	b.BindAction(a)
+	return b
+}
+
+// BindAction makes the button show and trigger a. Clicking the button calls
+// a.Trigger instead of OnClick. Nil unbinds the button.
+func (b *Button) BindAction(a *Action) {
+	if b.action != nil {
+		b.action.unbind(b)
+	}
+	b.action = a
+	if a != nil {
+		a.bind(b)
+	}
+}
+
+// sync copies the state of the bound action.
+func (b *Button) sync() {
+	a := b.action
+	if a == nil {
+		return
+	}
+	b.Icon = a.Icon
+	// Buttons have no mnemonics
+	b.Text, _, _ = parseMnemonic(a.Text)
+	b.Tooltip = a.Tooltip
+	if b.flat && a.Icon != IconNone {
+		b.Text = ""
+		if b.Tooltip == "" {
+			b.Tooltip, _, _ = parseMnemonic(a.Text)
+		}
+	}
+	b.Disabled = a.Disabled
+	b.Checked = a.Checkable && a.Checked
+}
+
+func (b *Button) GetTooltip() string {
+	return b.Tooltip
+}
+
 func (b *Button) MinSize() Size {
 	theme := b.GetTheme()
 	if theme == nil || theme.Font == nil {
@@ -32,23 +88,34 @@ func (b *Button) MinSize() Size {
 	height := (metrics.Ascent + metrics.Descent).Ceil()
 
 	if b.Icon != IconNone {
-		width += IconSize
+		width += theme.IconSize()
 		if b.Text != "" {
 			width += theme.Spacing
 		}
-		if IconSize > height {
-			height = IconSize
+		if theme.IconSize() > height {
+			height = theme.IconSize()
 		}
 	}
 
-	return Size{width + (theme.Padding.Left+theme.Padding.Right)*2, height + (theme.Padding.Top+theme.Padding.Bottom)*2}
+	pad := theme.Style(StyleButton, 0).Padding
+	return Size{width + pad.Left + pad.Right, height + pad.Top + pad.Bottom}
 }
 
 func (b *Button) Event(e Event) bool {
+	if b.Disabled {
+		b.hovered = false
+		b.pressed = false
+		return false
+	}
 	switch evt := e.(type) {
 	case MouseEvent:
 		inRect := b.Rect.Contains(evt.Pos)
 
+		if evt.TypeVal == EventMouseLeave {
+			b.hovered = false
+			return false
+		}
+
 		if evt.TypeVal == EventMouseMove {
 			wasHovered := b.hovered
 			b.hovered = inRect
@@ -62,17 +129,66 @@ func (b *Button) Event(e Event) bool {
 
 		if evt.TypeVal == EventMouseUp {
 			if b.pressed && inRect {
-				if b.OnClick != nil {
-					b.OnClick()
-				}
+				b.Activate()
 			}
 			b.pressed = false
 			return inRect
 		}
+	case KeyEvent:
+		if b.focused && evt.TypeVal == EventKeyDown && (evt.Key == KeyEnter || evt.Key == KeySpace) {
+			b.Activate()
+			return true
+		}
 	}
 	return false
 }
 
+// Activate clicks the button.
+func (b *Button) Activate() {
+	if b.action != nil {
+		b.action.Trigger()
+	} else if b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+func (b *Button) Focus() {
+	b.focused = true
+}
+
+func (b *Button) Unfocus() {
+	b.focused = false
+}
+
+// styleState returns the state the button is drawn in.
+func (b *Button) styleState() StyleState {
+	state := b.state()
+	if b.hovered {
+		state |= StateHovered
+	}
+	if b.pressed {
+		state |= StatePressed
+	}
+	if b.focused {
+		state |= StateFocused
+	}
+	if b.Checked {
+		state |= StateSelected
+	}
+	return state
+}
+
+func (b *Button) GrabsPointer() bool {
+	return b.pressed
+}
+
+func (b *Button) FindWidgetAt(pos q2d.Point) Widget {
+	if b.Rect.Contains(pos) {
+		return b
+	}
+	return nil
+}
+
 func (b *Button) Draw(img *q2d.Image) {
 	theme := b.GetTheme()
 	if theme == nil {
@@ -82,19 +198,17 @@ func (b *Button) Draw(img *q2d.Image) {
 	img.PushSubImage(b.Rect)
 	defer img.PopSubImage()
 
-	bgColor := theme.ButtonColor
-	if b.pressed {
-		bgColor = bgColor.Darken(0.2)
-	} else if b.hovered {
-		bgColor = theme.ButtonHoverColor
+	st := theme.Style(StyleButton, b.styleState())
+	if !b.flat || b.pressed || b.Checked || b.hovered {
+		DrawBox(img, q2d.Rectangle{0, 0, b.Rect.Width(), b.Rect.Height()}, st)
+	} else if b.focused {
+		DrawBox(img, q2d.Rectangle{0, 0, b.Rect.Width(), b.Rect.Height()}, Style{Border: st.Border, BorderWidth: st.BorderWidth, Radius: st.Radius})
 	}
-
-	img.Fill(bgColor)
-	img.Border(theme.BorderColor)
+	textColor := st.Text
 
 	textWidth := font.MeasureString(theme.Font, b.Text).Ceil()
 	if b.Icon != IconNone {
-		textWidth += IconSize
+		textWidth += theme.IconSize()
 		if b.Text != "" {
 			textWidth += theme.Spacing
 		}
@@ -104,8 +218,8 @@ func (b *Button) Draw(img *q2d.Image) {
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	// Use max height of text or icon
 	contentHeight := textHeight
-	if b.Icon != IconNone && IconSize > contentHeight {
-		contentHeight = IconSize
+	if b.Icon != IconNone && theme.IconSize() > contentHeight {
+		contentHeight = theme.IconSize()
 	}
 
 	x := (b.Rect.Width() - textWidth) / 2
@@ -120,11 +234,11 @@ func (b *Button) Draw(img *q2d.Image) {
 	}
 
 	if b.Icon != IconNone {
-		iconY := y + (contentHeight-IconSize)/2
-		DrawIcon(img, b.Icon, q2d.Point{x, iconY}, theme.TextColor)
-		x += IconSize + theme.Spacing
+		iconY := y + (contentHeight-theme.IconSize())/2
+		theme.DrawIcon(img, b.Icon, q2d.Point{x, iconY}, textColor)
+		x += theme.IconSize() + theme.Spacing
 	}
 
 	textY := y + (contentHeight-textHeight)/2
-	img.Text(q2d.Point{x, textY}, theme.TextColor, theme.Font, false, "%s", b.Text)
+	img.Text(q2d.Point{x, textY}, textColor, theme.Font, false, "%s", b.Text)
 }
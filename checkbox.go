@@ -25,19 +25,21 @@ func NewCheckbox(label string, checked bool, onChange func(bool)) *Checkbox {
 }
 
 func (c *Checkbox) MinSize() Size {
-	if DefaultTheme == nil || DefaultTheme.Font == nil {
+	theme := c.GetTheme()
+	if theme == nil || theme.Font == nil {
 		return Size{0, 0}
 	}
-	width := IconSize + DefaultTheme.Spacing
-	width += font.MeasureString(DefaultTheme.Font, c.Label).Ceil()
+	width := theme.IconSize() + theme.Spacing
+	width += font.MeasureString(theme.Font, c.Label).Ceil()
 
-	metrics := DefaultTheme.Font.Metrics()
+	metrics := theme.Font.Metrics()
 	height := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > height {
-		height = IconSize
+	if theme.IconSize() > height {
+		height = theme.IconSize()
 	}
 
-	return Size{width + (DefaultTheme.Padding.Left + DefaultTheme.Padding.Right), height + (DefaultTheme.Padding.Top + DefaultTheme.Padding.Bottom)}
+	pad := theme.Style(StyleCheck, 0).Padding
+	return Size{width + pad.Left + pad.Right, height + pad.Top + pad.Bottom}
 }
 
 func (c *Checkbox) Event(e Event) bool {
@@ -45,6 +47,11 @@ func (c *Checkbox) Event(e Event) bool {
 	case MouseEvent:
 		inRect := c.Rect.Contains(evt.Pos)
 
+		if evt.TypeVal == EventMouseLeave {
+			c.hovered = false
+			return false
+		}
+
 		if evt.TypeVal == EventMouseMove {
 			wasHovered := c.hovered
 			c.hovered = inRect
@@ -81,6 +88,11 @@ func (c *Checkbox) Toggle() {
 	}
 }
 
+// Activate toggles the checkbox.
+func (c *Checkbox) Activate() {
+	c.Toggle()
+}
+
 func (c *Checkbox) Focus() {
 	c.focused = true
 }
@@ -96,17 +108,34 @@ func (c *Checkbox) FindWidgetAt(pos q2d.Point) Widget {
 	return nil
 }
 
+func (c *Checkbox) GrabsPointer() bool {
+	return c.pressed
+}
+
 func (c *Checkbox) Draw(img *q2d.Image) {
-	if DefaultTheme == nil {
+	theme := c.GetTheme()
+	if theme == nil {
 		return
 	}
 
 	img.PushSubImage(c.Rect)
 	defer img.PopSubImage()
 
+	state := c.state()
+	if c.hovered {
+		state |= StateHovered
+	}
+	if c.pressed {
+		state |= StatePressed
+	}
 	if c.focused {
-		img.Fill(DefaultTheme.BackgroundColor.Lighten(0.1))
+		state |= StateFocused
+	}
+	if c.Checked {
+		state |= StateSelected
 	}
+	st := theme.Style(StyleCheck, state)
+	DrawBox(img, q2d.Rectangle{0, 0, c.Rect.Width(), c.Rect.Height()}, st)
 
 	// Draw Icon
 	icon := IconUncheck
@@ -114,11 +143,11 @@ func (c *Checkbox) Draw(img *q2d.Image) {
 		icon = IconCheck
 	}
 
-	metrics := DefaultTheme.Font.Metrics()
+	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	contentHeight := textHeight
-	if IconSize > contentHeight {
-		contentHeight = IconSize
+	if theme.IconSize() > contentHeight {
+		contentHeight = theme.IconSize()
 	}
 
 	y := (c.Rect.Height() - contentHeight) / 2
@@ -126,10 +155,10 @@ func (c *Checkbox) Draw(img *q2d.Image) {
 		y = 0
 	}
 
-	iconY := y + (contentHeight-IconSize)/2
-	DrawIcon(img, icon, q2d.Point{DefaultTheme.Padding.Left, iconY}, DefaultTheme.TextColor)
+	iconY := y + (contentHeight-theme.IconSize())/2
+	theme.DrawIcon(img, icon, q2d.Point{st.Padding.Left, iconY}, st.Text)
 
-	textX := DefaultTheme.Padding.Left + IconSize + DefaultTheme.Spacing
+	textX := st.Padding.Left + theme.IconSize() + theme.Spacing
 	textY := y + (contentHeight-textHeight)/2
-	img.Text(q2d.Point{textX, textY}, DefaultTheme.TextColor, DefaultTheme.Font, false, "%s", c.Label)
+	img.Text(q2d.Point{textX, textY}, st.Text, theme.Font, false, "%s", c.Label)
 }
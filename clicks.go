@@ -0,0 +1,77 @@
+package qui
+
+import (
+	"time"
+
+	"github.com/qbradq/q2d"
+)
+
+// DoubleClickTime is the longest interval between two presses that still
+// counts them as a multiple click.
+var DoubleClickTime = 400 * time.Millisecond
+
+// DoubleClickDistance is how far in pixels the pointer may move between the
+// presses of a multiple click, or while a button is held for a long press.
+// It is scaled by Master.Scale, as are the other distances.
+var DoubleClickDistance = 4
+
+// LongPressTime is how long a button has to be held for a long press.
+var LongPressTime = 600 * time.Millisecond
+
+// nearPress returns true if p is close enough to the last press for click
+// counting and long presses.
+func (m *Master) nearPress(p q2d.Point) bool {
+	d := p.Sub(m.pressPos)
+	dist := m.px(DoubleClickDistance)
+	return abs(d.X()) <= dist && abs(d.Y()) <= dist
+}
+
+// countClicks fills in the click count of a press or release.
+func (m *Master) countClicks(e MouseEvent) MouseEvent {
+	switch e.TypeVal {
+	case EventMouseDown:
+		now := time.Now()
+		if m.clicks > 0 && e.Button == m.pressButton && now.Sub(m.pressTime) <= DoubleClickTime && m.nearPress(e.Pos) {
+			m.clicks++
+		} else {
+			m.clicks = 1
+		}
+		m.pressTime = now
+		m.pressPos = e.Pos
+		m.pressButton = e.Button
+		m.longPressArmed = true
+	case EventMouseMove:
+		if m.longPressArmed && !m.nearPress(e.Pos) {
+			m.longPressArmed = false
+		}
+	case EventMouseUp:
+		m.longPressArmed = false
+	}
+	e.Clicks = m.clicks
+	return e
+}
+
+// checkLongPress sends EventLongPress once the pressed button has been held
+// long enough.
+func (m *Master) checkLongPress() {
+	if !m.longPressArmed || time.Since(m.pressTime) < LongPressTime {
+		return
+	}
+	m.longPressArmed = false
+	e := MouseEvent{
+		TypeVal: EventLongPress,
+		Pos:     m.pressPos,
+		Button:  m.pressButton,
+		Clicks:  m.clicks,
+	}
+	target := m.pressTarget
+	// The release after a long press is not a click
+	m.pressTarget = nil
+	m.clicks = 0
+	if !interactive(target) {
+		return
+	}
+	if !m.dispatch(target, e) {
+		m.deliver(target, e)
+	}
+}
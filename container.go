@@ -19,14 +19,43 @@ type Container struct {
 }
 
 func NewContainer(dir LayoutDirection, children ...Widget) *Container {
-	return &Container{
+	c := &Container{
 		Children:  children,
 		Direction: dir,
 	}
+	adopt(c, children...)
+	return c
 }
 
 func (c *Container) Add(w Widget) {
 	c.Children = append(c.Children, w)
+	adopt(c, w)
+}
+
+// Remove removes w from the children of the container.
+func (c *Container) Remove(w Widget) {
+	for i, child := range c.Children {
+		if child == w {
+			c.Children = append(c.Children[:i], c.Children[i+1:]...)
+			setParent(w, nil)
+			return
+		}
+	}
+}
+
+func (c *Container) GetChildren() []Widget {
+	return c.Children
+}
+
+// visibleChildren returns the children that are not hidden.
+func (c *Container) visibleChildren() []Widget {
+	ret := make([]Widget, 0, len(c.Children))
+	for _, child := range c.Children {
+		if shown(child) {
+			ret = append(ret, child)
+		}
+	}
+	return ret
 }
 
 func (c *Container) MinSize() Size {
@@ -36,15 +65,16 @@ func (c *Container) MinSize() Size {
 		spacing = theme.Spacing
 	}
 
+	children := c.visibleChildren()
 	w, h := 0, 0
-	for i, child := range c.Children {
+	for i, child := range children {
 		sz := child.MinSize()
 		if c.Direction == LayoutVertical {
 			if sz.Width > w {
 				w = sz.Width
 			}
 			h += sz.Height
-			if i < len(c.Children)-1 {
+			if i < len(children)-1 {
 				h += spacing
 			}
 		} else {
@@ -52,7 +82,7 @@ func (c *Container) MinSize() Size {
 				h = sz.Height
 			}
 			w += sz.Width
-			if i < len(c.Children)-1 {
+			if i < len(children)-1 {
 				w += spacing
 			}
 		}
@@ -67,16 +97,20 @@ func (c *Container) Layout(available Size) Size {
 		spacing = theme.Spacing
 	}
 
+	// Children may have been assigned directly
+	adopt(c, c.Children...)
+
 	// Calculate total spacing
+	children := c.visibleChildren()
 	totalSpacing := 0
-	if len(c.Children) > 1 {
-		totalSpacing = spacing * (len(c.Children) - 1)
+	if len(children) > 1 {
+		totalSpacing = spacing * (len(children) - 1)
 	}
 
 	// First pass: Calculate space taken by non-fill widgets
 	usedSpace := 0
 	fillCount := 0
-	for _, child := range c.Children {
+	for _, child := range children {
 		if child.IsFill() {
 			fillCount++
 		} else {
@@ -107,7 +141,7 @@ func (c *Container) Layout(available Size) Size {
 	x, y := c.Rect.X(), c.Rect.Y()
 	totalW, totalH := 0, 0
 
-	for i, child := range c.Children {
+	for i, child := range children {
 		sz := child.MinSize()
 
 		// Determine size for this child
@@ -157,7 +191,7 @@ func (c *Container) Layout(available Size) Size {
 			totalW += childW
 		}
 
-		if i < len(c.Children)-1 {
+		if i < len(children)-1 {
 			if c.Direction == LayoutVertical {
 				totalH += spacing
 			} else {
@@ -171,17 +205,10 @@ func (c *Container) Layout(available Size) Size {
 
 func (c *Container) Draw(img *q2d.Image) {
 	for _, child := range c.Children {
-		child.Draw(img)
-	}
-}
-
-func (c *Container) Event(e Event) bool {
-	for _, child := range c.Children {
-		if child.Event(e) {
-			return true
+		if shown(child) {
+			child.Draw(img)
 		}
 	}
-	return false
 }
 
 func (c *Container) FindWidgetAt(pos q2d.Point) Widget {
@@ -191,6 +218,9 @@ func (c *Container) FindWidgetAt(pos q2d.Point) Widget {
 	// Check children in reverse order (top-most first)
 	for i := len(c.Children) - 1; i >= 0; i-- {
 		child := c.Children[i]
+		if !shown(child) {
+			continue
+		}
 		if w := child.FindWidgetAt(pos); w != nil {
 			return w
 		}
@@ -0,0 +1,208 @@
+package qui
+
+import (
+	"math"
+
+	"github.com/qbradq/q2d"
+)
+
+// ContrastLevel is a WCAG 2 conformance level for the contrast of text.
+type ContrastLevel int
+
+const (
+	// ContrastAA requires a contrast ratio of at least 4.5:1.
+	ContrastAA ContrastLevel = iota
+	// ContrastAAA requires a contrast ratio of at least 7:1.
+	ContrastAAA
+)
+
+// Ratio returns the least contrast ratio of the level.
+func (l ContrastLevel) Ratio() float64 {
+	if l == ContrastAAA {
+		return 7
+	}
+	return 4.5
+}
+
+// Luminance returns the relative luminance of c as defined by WCAG 2, from 0
+// for black to 1 for white. Alpha is ignored.
+func Luminance(c q2d.Color) float64 {
+	channel := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.04045 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R()) + 0.7152*channel(c.G()) + 0.0722*channel(c.B())
+}
+
+// ContrastRatio returns the WCAG 2 contrast ratio of two colors, from 1 for
+// the same colors to 21 for black and white.
+func ContrastRatio(a, b q2d.Color) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Text colors picked by ReadableTextColor
+var (
+	lightText = q2d.Color{240, 240, 240, 255}
+	darkText  = q2d.Color{20, 20, 20, 255}
+)
+
+// ReadableTextColor returns a light or a dark text color, whichever contrasts
+// more with bg.
+func ReadableTextColor(bg q2d.Color) q2d.Color {
+	if ContrastRatio(lightText, bg) >= ContrastRatio(darkText, bg) {
+		return lightText
+	}
+	return darkText
+}
+
+// AdjustContrast returns fg with its lightness changed as little as needed to
+// reach a contrast ratio of at least ratio with bg. If that cannot be
+// reached, the color of the highest contrast is returned.
+func AdjustContrast(fg, bg q2d.Color, ratio float64) q2d.Color {
+	if ContrastRatio(fg, bg) >= ratio {
+		return fg
+	}
+	h, s, l := fg.ToHSL()
+	best := fg
+	for step := 0.01; step <= 1; step += 0.01 {
+		for _, nl := range []float64{l + step, l - step} {
+			if nl < 0 || nl > 1 {
+				continue
+			}
+			c := q2d.FromHSL(h, s, nl, fg.A())
+			if ContrastRatio(c, bg) >= ratio {
+				return c
+			}
+			if ContrastRatio(c, bg) > ContrastRatio(best, bg) {
+				best = c
+			}
+		}
+	}
+	return best
+}
+
+// ContrastIssue is text in a theme that does not contrast enough with its
+// background.
+type ContrastIssue struct {
+	// Key names where the text is drawn the way keys of theme files do, such
+	// as "text_color" for labels or "styles.list_item.selected" for the text
+	// of selected list items.
+	Key        string
+	Text       q2d.Color
+	Background q2d.Color
+	Ratio      float64
+	// Required is the ratio of the level checked against.
+	Required float64
+}
+
+// Keys of the StyleKinds in theme files
+var styleKindKeys = []string{"button", "input", "list", "list_item", "check", "select", "tab", "menu_item", "window", "header", "menu", "menu_bar", "tab_bar", "panel", "inset", "tooltip", "scroll_bar", "scroll_thumb", "divider", "drag", "drop_target"}
+
+// States whose contrast is checked. Disabled widgets are exempt from the
+// contrast requirements of WCAG.
+var contrastStates = []struct {
+	key   string
+	state StyleState
+}{
+	{"normal", 0},
+	{"hovered", StateHovered},
+	{"pressed", StatePressed},
+	{"focused", StateFocused},
+	{"selected", StateSelected},
+}
+
+// CheckContrast returns the text of the theme that falls below level: the
+// TextColor on the BackgroundColor, and the text of each kind of widget in
+// each state except disabled on its background, see Theme.Style. Styles
+// without a background are checked against the BackgroundColor, and text on
+// a gradient against the end it contrasts less with. States that look the same
+// as the normal state are not reported again.
+func (t *Theme) CheckContrast(level ContrastLevel) []ContrastIssue {
+	required := level.Ratio()
+	var issues []ContrastIssue
+	check := func(key string, text, bg q2d.Color) {
+		if r := ContrastRatio(text, bg); r < required {
+			issues = append(issues, ContrastIssue{
+				Key:        key,
+				Text:       text,
+				Background: bg,
+				Ratio:      r,
+				Required:   required,
+			})
+		}
+	}
+	check("text_color", t.TextColor, t.BackgroundColor)
+	for k, kindKey := range styleKindKeys {
+		for _, s := range contrastStates {
+			st := t.Style(StyleKind(k), s.state)
+			if st.Text.A() == 0 || s.state != 0 && st == t.Style(StyleKind(k), 0) {
+				// No text, or reported as normal
+				continue
+			}
+			check("styles."+kindKey+"."+s.key, st.Text, t.textBackground(st))
+		}
+	}
+	return issues
+}
+
+// FixContrast changes the text colors that CheckContrast reports for level as
+// little as needed to pass. TextColor is fixed first, then the text of the
+// styles that still fall below level is overridden in Styles. It returns the
+// issues that remain because no text color reaches the level on their
+// background, such as a mid-tone PrimaryColor at ContrastAAA; those need a
+// different background.
+func (t *Theme) FixContrast(level ContrastLevel) []ContrastIssue {
+	if len(t.CheckContrast(level)) == 0 {
+		return nil
+	}
+	required := level.Ratio()
+	t.TextColor = AdjustContrast(t.TextColor, t.BackgroundColor, required)
+	for k := range styleKindKeys {
+		for _, s := range contrastStates {
+			st := t.Style(StyleKind(k), s.state)
+			bg := t.textBackground(st)
+			if st.Text.A() == 0 || ContrastRatio(st.Text, bg) >= required {
+				continue
+			}
+			t.Styles.kind(StyleKind(k)).style(s.state).Text = AdjustContrast(st.Text, bg, required)
+		}
+	}
+	return t.CheckContrast(level)
+}
+
+// textBackground returns the color text of st is drawn on, or the end of its
+// gradient the text contrasts less with.
+func (t *Theme) textBackground(st Style) q2d.Color {
+	bg := st.Background
+	if bg.A() == 0 {
+		bg = t.BackgroundColor
+	}
+	if st.Gradient.A() > 0 && ContrastRatio(st.Text, st.Gradient) < ContrastRatio(st.Text, bg) {
+		return st.Gradient
+	}
+	return bg
+}
+
+// style returns the style of a single state.
+func (s *StateStyles) style(state StyleState) *Style {
+	switch state {
+	case StateHovered:
+		return &s.Hovered
+	case StatePressed:
+		return &s.Pressed
+	case StateFocused:
+		return &s.Focused
+	case StateDisabled:
+		return &s.Disabled
+	case StateSelected:
+		return &s.Selected
+	}
+	return &s.Normal
+}
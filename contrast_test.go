@@ -0,0 +1,119 @@
+package qui
+
+import (
+	"math"
+	"testing"
+
+	"github.com/qbradq/q2d"
+)
+
+var (
+	black = q2d.Color{0, 0, 0, 255}
+	white = q2d.Color{255, 255, 255, 255}
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b q2d.Color
+		want float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{black, black, 1},
+		{q2d.Color{119, 119, 119, 255}, white, 4.48},
+		{q2d.Color{118, 118, 118, 255}, white, 4.54},
+		{q2d.Color{89, 89, 89, 255}, white, 7.0},
+		{q2d.Color{255, 0, 0, 255}, white, 4.00},
+		{q2d.Color{0, 0, 255, 255}, white, 8.59},
+		{q2d.Color{0, 128, 0, 255}, white, 5.14},
+		{q2d.Color{255, 0, 0, 255}, q2d.Color{0, 255, 0, 255}, 2.91},
+		{q2d.Color{0, 0, 0, 0}, q2d.Color{255, 255, 255, 0}, 21},
+	}
+	for _, tt := range tests {
+		if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%v, %v) = %.3f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAdjustContrast(t *testing.T) {
+	gray := q2d.Color{128, 128, 128, 255}
+	tests := []struct {
+		fg, bg q2d.Color
+		ratio  float64
+	}{
+		{q2d.Color{119, 119, 119, 255}, white, 4.5},
+		{q2d.Color{119, 119, 119, 255}, white, 7},
+		{q2d.Color{60, 60, 60, 255}, black, 4.5},
+		{q2d.Color{200, 60, 60, 255}, white, 4.5},
+		{q2d.Color{40, 80, 200, 128}, q2d.Color{30, 30, 40, 255}, 7},
+		{gray, gray, 4.5},
+		{black, white, 21},
+	}
+	for _, tt := range tests {
+		got := AdjustContrast(tt.fg, tt.bg, tt.ratio)
+		if r := ContrastRatio(got, tt.bg); r < tt.ratio {
+			t.Errorf("AdjustContrast(%v, %v, %v) = %v with ratio %.3f", tt.fg, tt.bg, tt.ratio, got, r)
+		}
+		if got.A() != tt.fg.A() {
+			t.Errorf("AdjustContrast(%v, %v, %v) changed alpha to %d", tt.fg, tt.bg, tt.ratio, got.A())
+		}
+		if ContrastRatio(tt.fg, tt.bg) >= tt.ratio && got != tt.fg {
+			t.Errorf("AdjustContrast(%v, %v, %v) = %v, want it unchanged", tt.fg, tt.bg, tt.ratio, got)
+		}
+	}
+
+	// A mid-tone background cannot reach 21:1, so the best color is returned.
+	got := AdjustContrast(gray, gray, 21)
+	if r := ContrastRatio(got, gray); r < 5 {
+		t.Errorf("AdjustContrast(gray, gray, 21) = %v with ratio %.3f, want the highest contrast", got, r)
+	}
+}
+
+func TestFixContrast(t *testing.T) {
+	tests := []struct {
+		name   string
+		theme  *Theme
+		level  ContrastLevel
+		remain bool
+	}{
+		{"dark", GenerateThemeVariant(q2d.Color{0, 120, 215, 255}, ThemeDark, nil), ContrastAA, false},
+		{"light", GenerateThemeVariant(q2d.Color{0, 120, 215, 255}, ThemeLight, nil), ContrastAA, false},
+		{"low contrast", GenerateTheme(q2d.Color{90, 90, 90, 255}, q2d.Color{130, 130, 130, 255}, q2d.Color{20, 20, 20, 255}, nil), ContrastAA, false},
+		{"light AAA", GenerateTheme(q2d.Color{235, 235, 235, 255}, q2d.Color{150, 150, 150, 255}, q2d.Color{0, 0, 120, 255}, nil), ContrastAAA, false},
+		{"mid-tone primary AAA", GenerateThemeVariant(q2d.Color{60, 160, 90, 255}, ThemeDark, nil), ContrastAAA, true},
+	}
+	for _, tt := range tests {
+		issues := tt.theme.FixContrast(tt.level)
+		if got := len(issues) != 0; got != tt.remain {
+			t.Errorf("%s: FixContrast left issues %+v, want some %v", tt.name, issues, tt.remain)
+		}
+		for _, is := range issues {
+			// Only text no color can fix may remain.
+			if best := max(ContrastRatio(black, is.Background), ContrastRatio(white, is.Background)); best >= is.Required {
+				t.Errorf("%s: FixContrast left %s at %.3f, %.3f is reachable", tt.name, is.Key, is.Ratio, best)
+			}
+		}
+		if again := tt.theme.CheckContrast(tt.level); len(again) != len(issues) {
+			t.Errorf("%s: CheckContrast after FixContrast = %+v, want %+v", tt.name, again, issues)
+		}
+		if r := ContrastRatio(tt.theme.TextColor, tt.theme.BackgroundColor); r < tt.level.Ratio() {
+			t.Errorf("%s: text_color ratio %.3f after FixContrast", tt.name, r)
+		}
+	}
+}
+
+func TestCheckContrastKeys(t *testing.T) {
+	theme := GenerateTheme(white, q2d.Color{119, 119, 119, 255}, q2d.Color{0, 0, 200, 255}, nil)
+	issues := theme.CheckContrast(ContrastAA)
+	if len(issues) == 0 || issues[0].Key != "text_color" {
+		t.Fatalf("CheckContrast = %+v, want text_color first", issues)
+	}
+	if r := issues[0].Ratio; math.Abs(r-4.48) > 0.01 || issues[0].Required != 4.5 {
+		t.Errorf("text_color issue ratio %.3f of %.1f, want 4.48 of 4.5", r, issues[0].Required)
+	}
+	if issues := theme.CheckContrast(ContrastLevel(-1)); len(issues) == 0 {
+		t.Errorf("CheckContrast of an unknown level found no issues, want AA checked")
+	}
+}
@@ -0,0 +1,277 @@
+package qui
+
+import "github.com/qbradq/q2d"
+
+// EventPhase is the stage of an event's trip through the widget tree.
+type EventPhase int
+
+const (
+	// PhaseCapture runs capture handlers from the root down to the parent of
+	// the target.
+	PhaseCapture EventPhase = iota
+	// PhaseTarget runs the capture and bubble handlers of the target itself.
+	PhaseTarget
+	// PhaseBubble runs bubble handlers from the parent of the target up to the
+	// root.
+	PhaseBubble
+)
+
+// EventContext is passed to event handlers.
+type EventContext struct {
+	Event Event
+	// Target is the widget under the mouse for mouse and scroll events and the
+	// focused widget for key, text and focus events.
+	Target Widget
+	// Current is the widget whose handler is running.
+	Current Widget
+	Phase   EventPhase
+
+	master    *Master
+	stopped   bool
+	prevented bool
+}
+
+// StopPropagation prevents the handlers of any further widgets from running.
+// The remaining handlers of the current widget still run.
+func (c *EventContext) StopPropagation() {
+	c.stopped = true
+}
+
+// PreventDefault stops the widgets' built-in handling of the event, such as a
+// button being pressed or focus moving on a click, and marks it as consumed.
+func (c *EventContext) PreventDefault() {
+	c.prevented = true
+}
+
+// CapturePointer sends all mouse events to the current widget until the
+// mouse button is released, see Master.CapturePointer.
+func (c *EventContext) CapturePointer() {
+	if c.master != nil {
+		c.master.CapturePointer(c.Current)
+	}
+}
+
+type eventHandler struct {
+	typ     EventType
+	capture bool
+	fn      func(ctx *EventContext)
+}
+
+// EventHandlers holds the handlers subscribed to the events of a widget. See
+// Widget.Handlers.
+type EventHandlers struct {
+	handlers   []eventHandler
+	dragSource DragSource
+	dropTarget DropTarget
+}
+
+// On subscribes fn to events of type t targeted at the widget or bubbling up
+// from its descendants.
+func (h *EventHandlers) On(t EventType, fn func(ctx *EventContext)) {
+	h.handlers = append(h.handlers, eventHandler{typ: t, fn: fn})
+}
+
+// OnCapture subscribes fn to events of type t on their way down to the widget
+// or its descendants, before any handlers registered with On.
+func (h *EventHandlers) OnCapture(t EventType, fn func(ctx *EventContext)) {
+	h.handlers = append(h.handlers, eventHandler{typ: t, capture: true, fn: fn})
+}
+
+func (h *EventHandlers) onMouse(t EventType, fn func(ctx *EventContext, e MouseEvent)) {
+	h.On(t, func(ctx *EventContext) {
+		fn(ctx, ctx.Event.(MouseEvent))
+	})
+}
+
+func (h *EventHandlers) onKey(t EventType, fn func(ctx *EventContext, e KeyEvent)) {
+	h.On(t, func(ctx *EventContext) {
+		fn(ctx, ctx.Event.(KeyEvent))
+	})
+}
+
+// OnClick subscribes fn to presses and releases of a mouse button over the
+// same widget.
+func (h *EventHandlers) OnClick(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventClick, fn)
+}
+
+func (h *EventHandlers) OnMouseDown(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventMouseDown, fn)
+}
+
+func (h *EventHandlers) OnMouseUp(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventMouseUp, fn)
+}
+
+func (h *EventHandlers) OnMouseMove(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventMouseMove, fn)
+}
+
+// OnMouseEnter subscribes fn to the pointer moving onto the widget.
+func (h *EventHandlers) OnMouseEnter(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventMouseEnter, fn)
+}
+
+// OnMouseLeave subscribes fn to the pointer moving off the widget.
+func (h *EventHandlers) OnMouseLeave(fn func(ctx *EventContext, e MouseEvent)) {
+	h.onMouse(EventMouseLeave, fn)
+}
+
+func (h *EventHandlers) OnKeyDown(fn func(ctx *EventContext, e KeyEvent)) {
+	h.onKey(EventKeyDown, fn)
+}
+
+func (h *EventHandlers) OnKeyUp(fn func(ctx *EventContext, e KeyEvent)) {
+	h.onKey(EventKeyUp, fn)
+}
+
+func (h *EventHandlers) OnTextInput(fn func(ctx *EventContext, e TextInputEvent)) {
+	h.On(EventTextInput, func(ctx *EventContext) {
+		fn(ctx, ctx.Event.(TextInputEvent))
+	})
+}
+
+func (h *EventHandlers) OnScroll(fn func(ctx *EventContext, e ScrollEvent)) {
+	h.On(EventScroll, func(ctx *EventContext) {
+		fn(ctx, ctx.Event.(ScrollEvent))
+	})
+}
+
+// OnFocus subscribes fn to the widget or one of its descendants gaining focus.
+func (h *EventHandlers) OnFocus(fn func(ctx *EventContext)) {
+	h.On(EventFocus, fn)
+}
+
+// OnBlur subscribes fn to the widget or one of its descendants losing focus.
+func (h *EventHandlers) OnBlur(fn func(ctx *EventContext)) {
+	h.On(EventBlur, fn)
+}
+
+// OnThemeChange subscribes fn to the theme of the widget's Master changing,
+// see Master.SetTheme.
+func (h *EventHandlers) OnThemeChange(fn func(ctx *EventContext, e ThemeEvent)) {
+	h.On(EventThemeChange, func(ctx *EventContext) {
+		fn(ctx, ctx.Event.(ThemeEvent))
+	})
+}
+
+// run calls the handlers for the context's event in the capture or bubble
+// phase.
+func (h *EventHandlers) run(ctx *EventContext, capture bool) {
+	if h == nil {
+		return
+	}
+	for _, eh := range h.handlers {
+		if eh.typ == ctx.Event.Type() && eh.capture == capture {
+			eh.fn(ctx)
+		}
+	}
+}
+
+// dispatch sends e to the handlers along the path from the root of target's
+// tree down to target and back up. It returns true if a handler called
+// PreventDefault.
+func (m *Master) dispatch(target Widget, e Event) bool {
+	if target == nil {
+		return false
+	}
+	// Target first, root last
+	path := append([]Widget{target}, Ancestors(target)...)
+	ctx := &EventContext{
+		Event:  e,
+		Target: target,
+		master: m,
+	}
+
+	ctx.Phase = PhaseCapture
+	for i := len(path) - 1; i > 0 && !ctx.stopped; i-- {
+		ctx.Current = path[i]
+		handlersOf(path[i]).run(ctx, true)
+	}
+	if !ctx.stopped {
+		ctx.Phase = PhaseTarget
+		ctx.Current = target
+		handlersOf(target).run(ctx, true)
+		handlersOf(target).run(ctx, false)
+	}
+	ctx.Phase = PhaseBubble
+	for i := 1; i < len(path) && !ctx.stopped; i++ {
+		ctx.Current = path[i]
+		handlersOf(path[i]).run(ctx, false)
+	}
+	return ctx.prevented
+}
+
+// notify sends e to the handlers of w alone, then to w itself. It neither
+// bubbles nor reaches the children of w.
+func (m *Master) notify(w Widget, e Event) {
+	if interactive(w) {
+		ctx := &EventContext{
+			Event:   e,
+			Target:  w,
+			Current: w,
+			Phase:   PhaseTarget,
+			master:  m,
+		}
+		handlersOf(w).run(ctx, true)
+		handlersOf(w).run(ctx, false)
+		if ctx.prevented {
+			return
+		}
+	}
+	w.Event(e)
+}
+
+// widgetAt returns the topmost widget at pos in the overlays or the root.
+func (m *Master) widgetAt(pos q2d.Point) Widget {
+	for i := len(m.Overlays) - 1; i >= 0; i-- {
+		if w := m.Overlays[i].FindWidgetAt(pos); w != nil {
+			return w
+		}
+	}
+	if m.Root != nil {
+		return m.Root.FindWidgetAt(pos)
+	}
+	return nil
+}
+
+// eventTarget returns the widget e is dispatched to.
+func (m *Master) eventTarget(e Event) Widget {
+	switch evt := e.(type) {
+	case MouseEvent:
+		if m.capture != nil {
+			return m.capture
+		}
+		return m.widgetAt(evt.Pos)
+	case ScrollEvent:
+		return m.widgetAt(m.MousePos)
+	case GestureEvent:
+		return m.widgetAt(evt.Pos)
+	}
+	if w, ok := m.FocusedWidget.(Widget); ok {
+		return w
+	}
+	return m.Root
+}
+
+// SetFocus moves the keyboard focus to f, which may be nil, sending blur and
+// focus events.
+func (m *Master) SetFocus(f Focusable) {
+	if m.FocusedWidget == f {
+		return
+	}
+	if old := m.FocusedWidget; old != nil {
+		m.FocusedWidget = nil
+		old.Unfocus()
+		if w, ok := old.(Widget); ok {
+			m.dispatch(w, FocusEvent{TypeVal: EventBlur})
+		}
+	}
+	if f != nil {
+		m.FocusedWidget = f
+		f.Focus()
+		if w, ok := f.(Widget); ok {
+			m.dispatch(w, FocusEvent{TypeVal: EventFocus})
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package qui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/qbradq/q2d"
+)
+
+// delivery is an event a recorder got.
+type delivery struct {
+	name string
+	typ  EventType
+}
+
+// recorder is a widget with children at fixed rectangles that logs the
+// events it gets.
+type recorder struct {
+	BaseWidget
+	name     string
+	children []Widget
+	consume  bool
+	log      *[]delivery
+}
+
+func newRecorder(name string, log *[]delivery, r q2d.Rectangle, children ...Widget) *recorder {
+	w := &recorder{name: name, children: children, log: log}
+	w.Rect = r
+	adopt(w, children...)
+	return w
+}
+
+func (r *recorder) Event(e Event) bool {
+	*r.log = append(*r.log, delivery{r.name, e.Type()})
+	return r.consume
+}
+
+func (r *recorder) GetChildren() []Widget {
+	return r.children
+}
+
+func (r *recorder) Layout(available Size) Size {
+	return available
+}
+
+func (r *recorder) FindWidgetAt(pos q2d.Point) Widget {
+	if !r.Rect.Contains(pos) {
+		return nil
+	}
+	for i := len(r.children) - 1; i >= 0; i-- {
+		if w := r.children[i].FindWidgetAt(pos); w != nil {
+			return w
+		}
+	}
+	return r
+}
+
+// recorderTree returns a root with a left half holding inner and a right
+// half, by name.
+func recorderTree(log *[]delivery) map[string]*recorder {
+	inner := newRecorder("inner", log, q2d.Rectangle{10, 10, 50, 50})
+	left := newRecorder("left", log, q2d.Rectangle{0, 0, 100, 100}, inner)
+	right := newRecorder("right", log, q2d.Rectangle{100, 0, 100, 100})
+	root := newRecorder("root", log, q2d.Rectangle{0, 0, 200, 100}, left, right)
+	return map[string]*recorder{"root": root, "left": left, "inner": inner, "right": right}
+}
+
+// received returns the names of the recorders in log that got events of type
+// t, in order.
+func received(log []delivery, t EventType) []string {
+	var ret []string
+	for _, d := range log {
+		if d.typ == t {
+			ret = append(ret, d.name)
+		}
+	}
+	return ret
+}
+
+func TestEventDelivery(t *testing.T) {
+	move := func(x, y int) Event { return MouseEvent{TypeVal: EventMouseMove, Pos: q2d.Point{x, y}} }
+	down := func(x, y int) Event { return MouseEvent{TypeVal: EventMouseDown, Pos: q2d.Point{x, y}} }
+	tests := []struct {
+		name     string
+		e        Event
+		consumer string
+		want     []string
+	}{
+		{"move bubbles to the root", move(20, 20), "", []string{"inner", "left", "root"}},
+		{"move stops where consumed", move(20, 20), "left", []string{"inner", "left"}},
+		{"press on a sibling", down(150, 50), "", []string{"right", "root"}},
+		{"press on a parent", down(80, 80), "", []string{"left", "root"}},
+		{"press on nothing", down(300, 300), "", nil},
+		{"scroll bubbles", ScrollEvent{TypeVal: EventScroll, DeltaY: 1}, "", []string{"inner", "left", "root"}},
+		{"key without focus goes to the root", KeyEvent{TypeVal: EventKeyDown, Key: 'A'}, "", []string{"root"}},
+	}
+	for _, tt := range tests {
+		var log []delivery
+		widgets := recorderTree(&log)
+		if c := widgets[tt.consumer]; c != nil {
+			c.consume = true
+		}
+		m := NewMaster(widgets["root"], nil)
+		m.Layout(Size{200, 100})
+		m.MousePos = q2d.Point{20, 20}
+		log = nil
+		m.Event(tt.e)
+		if got := received(log, tt.e.Type()); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: delivered to %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHoverNotifiesOnlyThePath(t *testing.T) {
+	var log []delivery
+	left := newRecorder("left", &log, q2d.Rectangle{0, 0, 100, 100})
+	right := newRecorder("right", &log, q2d.Rectangle{100, 0, 100, 100})
+	for _, r := range []*recorder{left, right} {
+		r := r
+		for _, typ := range []EventType{EventMouseEnter, EventMouseLeave} {
+			r.Handlers().On(typ, func(ctx *EventContext) {
+				log = append(log, delivery{r.name + " handler", ctx.Event.Type()})
+			})
+		}
+	}
+	c := NewContainer(LayoutHorizontal, left, right)
+	c.Rect = q2d.Rectangle{0, 0, 200, 100}
+	m := NewMaster(c, nil)
+
+	steps := []struct {
+		pos          q2d.Point
+		enter, leave []string
+	}{
+		{q2d.Point{50, 50}, []string{"left handler", "left"}, nil},
+		{q2d.Point{60, 50}, nil, nil},
+		{q2d.Point{150, 50}, []string{"right handler", "right"}, []string{"left handler", "left"}},
+		{q2d.Point{300, 50}, nil, []string{"right handler", "right"}},
+	}
+	for _, s := range steps {
+		log = nil
+		m.Event(MouseEvent{TypeVal: EventMouseMove, Pos: s.pos})
+		if got := received(log, EventMouseEnter); !slices.Equal(got, s.enter) {
+			t.Errorf("move to %v: enter delivered to %v, want %v", s.pos, got, s.enter)
+		}
+		if got := received(log, EventMouseLeave); !slices.Equal(got, s.leave) {
+			t.Errorf("move to %v: leave delivered to %v, want %v", s.pos, got, s.leave)
+		}
+	}
+}
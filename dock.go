@@ -0,0 +1,837 @@
+package qui
+
+import (
+	"fmt"
+
+	"github.com/qbradq/q2d"
+)
+
+type DockSide int
+
+const (
+	DockCenter DockSide = iota
+	DockLeft
+	DockRight
+	DockTop
+	DockBottom
+)
+
+const (
+	// dockDragThreshold is how far a tab must be dragged before it tears off.
+	dockDragThreshold = 5
+	// dockEdgeRatio is the share of the area given to panels docked against
+	// the outer edges of a DockArea.
+	dockEdgeRatio = 0.25
+)
+
+// DockPanel is a titled piece of content that can be docked, stacked as a tab
+// or floated by a DockArea. The ID identifies the panel in saved layouts.
+type DockPanel struct {
+	ID      string
+	Title   string
+	Icon    Icon
+	Content Widget
+}
+
+func NewDockPanel(id, title string, content Widget) *DockPanel {
+	return &DockPanel{
+		ID:      id,
+		Title:   title,
+		Content: content,
+	}
+}
+
+// dockNode is a node of the dock layout tree. Split nodes have two children
+// shown in a Splitter, leaf nodes hold a stack of panels shown in a
+// TabContainer.
+type dockNode struct {
+	parent   *dockNode
+	children []*dockNode
+	panels   []*DockPanel
+	splitter *Splitter
+	tabs     *TabContainer
+}
+
+func newDockLeaf(panels ...*DockPanel) *dockNode {
+	n := &dockNode{
+		panels: panels,
+		tabs:   NewTabContainer(),
+	}
+	n.syncTabs()
+	return n
+}
+
+func newDockSplit(dir LayoutDirection, first, second *dockNode, ratio float64) *dockNode {
+	n := &dockNode{
+		children: []*dockNode{first, second},
+		splitter: NewSplitter(dir, first.widget(), second.widget()),
+	}
+	n.splitter.Panes[0].Size = ratio
+	n.splitter.Panes[1].Size = 1 - ratio
+	first.parent = n
+	second.parent = n
+	return n
+}
+
+func (n *dockNode) isLeaf() bool {
+	return n.tabs != nil
+}
+
+func (n *dockNode) widget() Widget {
+	if n.isLeaf() {
+		return n.tabs
+	}
+	return n.splitter
+}
+
+// syncTabs rebuilds the tabs of a leaf node from its panels.
+func (n *dockNode) syncTabs() {
+	tabs := make([]Tab, len(n.panels))
+	for i, p := range n.panels {
+		tabs[i] = Tab{Title: p.Title, Icon: p.Icon, Content: p.Content}
+	}
+	n.tabs.Tabs = tabs
+	if n.tabs.ActiveTab >= len(tabs) {
+		n.tabs.ActiveTab = len(tabs) - 1
+	}
+	if n.tabs.ActiveTab < 0 {
+		n.tabs.ActiveTab = 0
+	}
+}
+
+// syncSplitter points the splitter of a split node at its children.
+func (n *dockNode) syncSplitter() {
+	n.splitter.Panes[0].Content = n.children[0].widget()
+	n.splitter.Panes[1].Content = n.children[1].widget()
+}
+
+// leaves appends all leaf nodes below n to list in depth-first order.
+func (n *dockNode) leaves(list []*dockNode) []*dockNode {
+	if n.isLeaf() {
+		return append(list, n)
+	}
+	for _, c := range n.children {
+		list = c.leaves(list)
+	}
+	return list
+}
+
+type dockFloat struct {
+	panel  *DockPanel
+	window *Window
+}
+
+// dockTarget is a drop indicator shown while a panel is dragged. A nil node
+// targets the outer edges of the whole DockArea.
+type dockTarget struct {
+	node *dockNode
+	side DockSide
+	rect q2d.Rectangle
+}
+
+// DockArea arranges DockPanels IDE-style. Panels can be docked to any side of
+// the area or of another panel, stacked as tabs, torn off into floating
+// windows by dragging their tab and re-docked by dragging their window onto
+// one of the drop indicators. Floating windows require OverlayManager to be
+// set.
+type DockArea struct {
+	BaseWidget
+	OverlayManager OverlayManager
+
+	root     *dockNode
+	panels   map[string]*DockPanel
+	floating []*dockFloat
+
+	pressed   bool
+	pressPos  q2d.Point
+	dragPanel *DockPanel
+	dragging  bool
+	preview   *dockPreview
+}
+
+func NewDockArea() *DockArea {
+	d := &DockArea{
+		panels: make(map[string]*DockPanel),
+	}
+	d.Fill = true
+	// Presses on tabs are seen on their way down, before the tab
+	// containers consume them
+	d.Handlers().OnCapture(EventMouseDown, func(ctx *EventContext) {
+		d.pressTab(ctx.Event.(MouseEvent).Pos)
+	})
+	return d
+}
+
+// RegisterPanel makes p known to the area without showing it, so it can be
+// placed later by RestoreLayout.
+func (d *DockArea) RegisterPanel(p *DockPanel) {
+	d.panels[p.ID] = p
+}
+
+// AddPanel docks p against the given side of the whole area. DockCenter adds p
+// as a tab to the largest panel stack.
+func (d *DockArea) AddPanel(p *DockPanel, side DockSide) {
+	d.RegisterPanel(p)
+	d.detach(p)
+	if side == DockCenter {
+		d.dock(p, d.centerLeaf(), DockCenter, 0.5)
+	} else {
+		d.dock(p, d.root, side, dockEdgeRatio)
+	}
+}
+
+// AddPanelBeside docks p against the given side of the stack holding target.
+// DockCenter adds p as a tab to that stack. If target is not docked p is
+// added to the area as with AddPanel.
+func (d *DockArea) AddPanelBeside(p, target *DockPanel, side DockSide) {
+	n, _ := d.findPanel(target)
+	if n == nil {
+		d.AddPanel(p, side)
+		return
+	}
+	d.RegisterPanel(p)
+	d.detach(p)
+	d.dock(p, n, side, 0.5)
+}
+
+// FloatPanel shows p in a floating window at rect.
+func (d *DockArea) FloatPanel(p *DockPanel, rect q2d.Rectangle) {
+	if d.OverlayManager == nil {
+		return
+	}
+	d.RegisterPanel(p)
+	d.detach(p)
+
+	f := &dockFloat{panel: p}
+	win := NewWindow(p.Title, p.Content)
+	win.Modeless = true
+	win.OnClose = func() {
+		d.removeFloat(f)
+	}
+	win.OnDrag = func(pos q2d.Point) {
+		d.updateDrag(pos, p)
+	}
+	win.OnDragEnd = func(pos q2d.Point) {
+		d.endWindowDrag(f, pos)
+	}
+	f.window = win
+	d.floating = append(d.floating, f)
+
+	attachOverlay(d.OverlayManager, win)
+	win.SetRect(rect)
+	win.Layout(Size{rect.Width(), rect.Height()})
+	d.OverlayManager.PushOverlay(win)
+}
+
+// ClosePanel removes p from the layout. The panel stays registered.
+func (d *DockArea) ClosePanel(p *DockPanel) {
+	d.detach(p)
+	if p.Content != nil {
+		setParent(p.Content, nil)
+	}
+}
+
+// findPanel returns the leaf holding p and its index, or nil if p is not
+// docked.
+func (d *DockArea) findPanel(p *DockPanel) (*dockNode, int) {
+	if d.root == nil {
+		return nil, -1
+	}
+	for _, leaf := range d.root.leaves(nil) {
+		for i, lp := range leaf.panels {
+			if lp == p {
+				return leaf, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// detach removes p from wherever it is currently shown.
+func (d *DockArea) detach(p *DockPanel) {
+	if n, i := d.findPanel(p); n != nil {
+		n.panels = append(n.panels[:i], n.panels[i+1:]...)
+		if len(n.panels) == 0 {
+			d.removeNode(n)
+		} else {
+			n.syncTabs()
+		}
+	}
+	for _, f := range d.floating {
+		if f.panel == p {
+			d.closeFloat(f)
+			break
+		}
+	}
+}
+
+// removeNode removes n from the tree, replacing its parent split with the
+// remaining sibling.
+func (d *DockArea) removeNode(n *dockNode) {
+	parent := n.parent
+	if parent == nil {
+		d.root = nil
+		return
+	}
+	sibling := parent.children[0]
+	if sibling == n {
+		sibling = parent.children[1]
+	}
+	d.replaceNode(parent, sibling)
+}
+
+// replaceNode puts n in the place of old within the tree.
+func (d *DockArea) replaceNode(old, n *dockNode) {
+	n.parent = old.parent
+	if old.parent == nil {
+		d.root = n
+		return
+	}
+	for i, c := range old.parent.children {
+		if c == old {
+			old.parent.children[i] = n
+		}
+	}
+	old.parent.syncSplitter()
+}
+
+// dock places p against the given side of target, or as a tab of target for
+// DockCenter. A nil target means the whole area.
+func (d *DockArea) dock(p *DockPanel, target *dockNode, side DockSide, ratio float64) {
+	if d.root == nil {
+		d.root = newDockLeaf(p)
+		return
+	}
+	if target == nil {
+		target = d.root
+	}
+	if side == DockCenter {
+		if !target.isLeaf() {
+			target = d.centerLeaf()
+		}
+		target.panels = append(target.panels, p)
+		target.syncTabs()
+		target.tabs.ActiveTab = len(target.panels) - 1
+		return
+	}
+
+	leaf := newDockLeaf(p)
+	var split *dockNode
+	parent := target.parent
+	switch side {
+	case DockLeft:
+		split = newDockSplit(LayoutHorizontal, leaf, target, ratio)
+	case DockRight:
+		split = newDockSplit(LayoutHorizontal, target, leaf, 1-ratio)
+	case DockTop:
+		split = newDockSplit(LayoutVertical, leaf, target, ratio)
+	default:
+		split = newDockSplit(LayoutVertical, target, leaf, 1-ratio)
+	}
+	// newDockSplit reparented target, restore it so replaceNode finds its slot
+	target.parent = parent
+	d.replaceNode(target, split)
+	target.parent = split
+}
+
+// centerLeaf returns the largest panel stack, which receives panels docked to
+// the center of the area.
+func (d *DockArea) centerLeaf() *dockNode {
+	if d.root == nil {
+		return nil
+	}
+	leaves := d.root.leaves(nil)
+	best := leaves[0]
+	bestArea := 0
+	for _, leaf := range leaves {
+		r := leaf.tabs.GetRect()
+		if a := r.Width() * r.Height(); a > bestArea {
+			best = leaf
+			bestArea = a
+		}
+	}
+	return best
+}
+
+func (d *DockArea) removeFloat(f *dockFloat) {
+	for i, ff := range d.floating {
+		if ff == f {
+			d.floating = append(d.floating[:i], d.floating[i+1:]...)
+			return
+		}
+	}
+}
+
+// closeFloat closes the window of f without triggering its close callback.
+func (d *DockArea) closeFloat(f *dockFloat) {
+	d.removeFloat(f)
+	f.window.OnClose = nil
+	if d.OverlayManager != nil {
+		removeOverlay(d.OverlayManager, f.window)
+	}
+}
+
+// tabAt returns the leaf and tab index of the tab header at pos.
+func (d *DockArea) tabAt(pos q2d.Point) (*dockNode, int) {
+	if d.root == nil {
+		return nil, -1
+	}
+	for _, leaf := range d.root.leaves(nil) {
+		if i := leaf.tabs.TabAt(pos); i >= 0 {
+			return leaf, i
+		}
+	}
+	return nil, -1
+}
+
+// targets returns the drop indicators to show for a drag of p at pos.
+func (d *DockArea) targets(pos q2d.Point, p *DockPanel) []dockTarget {
+	if d.root == nil {
+		return []dockTarget{{side: DockCenter, rect: d.indicatorRect(d.Rect, 0, 0)}}
+	}
+	var ret []dockTarget
+	for _, leaf := range d.root.leaves(nil) {
+		r := leaf.tabs.GetRect()
+		if !r.Contains(pos) {
+			continue
+		}
+		if len(leaf.panels) == 1 && leaf.panels[0] == p {
+			break
+		}
+		ret = append(ret,
+			dockTarget{leaf, DockCenter, d.indicatorRect(r, 0, 0)},
+			dockTarget{leaf, DockLeft, d.indicatorRect(r, -1, 0)},
+			dockTarget{leaf, DockRight, d.indicatorRect(r, 1, 0)},
+			dockTarget{leaf, DockTop, d.indicatorRect(r, 0, -1)},
+			dockTarget{leaf, DockBottom, d.indicatorRect(r, 0, 1)},
+		)
+		break
+	}
+
+	// Indicators for the outer edges of the area
+	s := d.indicatorSize()
+	r := d.Rect
+	cx := r.X() + (r.Width()-s)/2
+	cy := r.Y() + (r.Height()-s)/2
+	ret = append(ret,
+		dockTarget{nil, DockLeft, q2d.Rectangle{r.X() + s/2, cy, s, s}},
+		dockTarget{nil, DockRight, q2d.Rectangle{r.X() + r.Width() - s - s/2, cy, s, s}},
+		dockTarget{nil, DockTop, q2d.Rectangle{cx, r.Y() + s/2, s, s}},
+		dockTarget{nil, DockBottom, q2d.Rectangle{cx, r.Y() + r.Height() - s - s/2, s, s}},
+	)
+	return ret
+}
+
+func (d *DockArea) indicatorSize() int {
+	return d.GetTheme().Px(IconSize + 8)
+}
+
+// indicatorRect returns the rectangle of a compass indicator offset by dx, dy
+// indicator steps from the center of r.
+func (d *DockArea) indicatorRect(r q2d.Rectangle, dx, dy int) q2d.Rectangle {
+	s := d.indicatorSize()
+	step := s + d.GetTheme().Px(2)
+	return q2d.Rectangle{
+		r.X() + (r.Width()-s)/2 + dx*step,
+		r.Y() + (r.Height()-s)/2 + dy*step,
+		s,
+		s,
+	}
+}
+
+// targetRect returns the region a panel dropped on t would occupy.
+func (d *DockArea) targetRect(t dockTarget) q2d.Rectangle {
+	r := d.Rect
+	ratio := dockEdgeRatio
+	if t.node != nil {
+		r = t.node.widget().GetRect()
+		ratio = 0.5
+	}
+	w := int(float64(r.Width()) * ratio)
+	h := int(float64(r.Height()) * ratio)
+	switch t.side {
+	case DockLeft:
+		return q2d.Rectangle{r.X(), r.Y(), w, r.Height()}
+	case DockRight:
+		return q2d.Rectangle{r.X() + r.Width() - w, r.Y(), w, r.Height()}
+	case DockTop:
+		return q2d.Rectangle{r.X(), r.Y(), r.Width(), h}
+	case DockBottom:
+		return q2d.Rectangle{r.X(), r.Y() + r.Height() - h, r.Width(), h}
+	}
+	return r
+}
+
+func (d *DockArea) targetAt(pos q2d.Point, p *DockPanel) *dockTarget {
+	for _, t := range d.targets(pos, p) {
+		if t.rect.Contains(pos) {
+			return &t
+		}
+	}
+	return nil
+}
+
+// updateDrag shows the drop indicators for a drag of p at pos.
+func (d *DockArea) updateDrag(pos q2d.Point, p *DockPanel) {
+	if d.OverlayManager == nil {
+		return
+	}
+	if d.preview == nil {
+		d.preview = &dockPreview{area: d}
+		d.preview.SetRect(d.Rect)
+		d.OverlayManager.PushOverlay(d.preview)
+	}
+	d.preview.targets = d.targets(pos, p)
+	d.preview.active = -1
+	for i, t := range d.preview.targets {
+		if t.rect.Contains(pos) {
+			d.preview.active = i
+		}
+	}
+}
+
+func (d *DockArea) hidePreview() {
+	if d.preview != nil {
+		removeOverlay(d.OverlayManager, d.preview)
+		d.preview = nil
+	}
+}
+
+// drop moves p to the target under pos. It returns false if there is none.
+func (d *DockArea) drop(p *DockPanel, pos q2d.Point) bool {
+	t := d.targetAt(pos, p)
+	if t == nil {
+		return false
+	}
+	d.detach(p)
+	if t.node == nil {
+		d.dock(p, d.root, t.side, dockEdgeRatio)
+	} else {
+		d.dock(p, t.node, t.side, 0.5)
+	}
+	d.Layout(Size{d.Rect.Width(), d.Rect.Height()})
+	return true
+}
+
+func (d *DockArea) endTabDrag(pos q2d.Point) {
+	d.hidePreview()
+	p := d.dragPanel
+	if d.drop(p, pos) {
+		return
+	}
+
+	// Tear the panel off into a floating window
+	size := Size{200, 150}
+	if n, _ := d.findPanel(p); n != nil {
+		r := n.tabs.GetRect()
+		size = Size{r.Width(), r.Height()}
+		if len(n.panels) == 1 && n.parent == nil {
+			// The last docked panel stays put
+			return
+		}
+	}
+	d.FloatPanel(p, q2d.Rectangle{pos.X() - size.Width/2, pos.Y(), size.Width, size.Height})
+	d.Layout(Size{d.Rect.Width(), d.Rect.Height()})
+}
+
+func (d *DockArea) endWindowDrag(f *dockFloat, pos q2d.Point) {
+	d.hidePreview()
+	d.drop(f.panel, pos)
+}
+
+func (d *DockArea) MinSize() Size {
+	if d.root == nil {
+		return Size{0, 0}
+	}
+	return d.root.widget().MinSize()
+}
+
+func (d *DockArea) GetChildren() []Widget {
+	if d.root == nil {
+		return nil
+	}
+	return []Widget{d.root.widget()}
+}
+
+func (d *DockArea) Layout(available Size) Size {
+	if d.root != nil {
+		w := d.root.widget()
+		adopt(d, w)
+		w.SetRect(d.Rect)
+		w.Layout(Size{d.Rect.Width(), d.Rect.Height()})
+	}
+	return available
+}
+
+func (d *DockArea) Event(e Event) bool {
+	if d.Disabled {
+		return false
+	}
+	if mouse, ok := e.(MouseEvent); ok {
+		switch mouse.TypeVal {
+		case EventMouseMove:
+			if d.dragging {
+				d.updateDrag(mouse.Pos, d.dragPanel)
+				return true
+			}
+			if d.pressed {
+				delta := mouse.Pos.Sub(d.pressPos)
+				threshold := d.GetTheme().Px(dockDragThreshold)
+				if abs(delta.X()) > threshold || abs(delta.Y()) > threshold {
+					d.dragging = true
+					d.updateDrag(mouse.Pos, d.dragPanel)
+					return true
+				}
+			}
+		case EventMouseUp:
+			d.pressed = false
+			if d.dragging {
+				d.dragging = false
+				d.endTabDrag(mouse.Pos)
+				d.dragPanel = nil
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pressTab starts tracking a press on the tab of a docked panel, which tears
+// the panel off once the pointer moves far enough.
+func (d *DockArea) pressTab(pos q2d.Point) {
+	if n, i := d.tabAt(pos); n != nil && !d.Disabled {
+		d.pressed = true
+		d.pressPos = pos
+		d.dragPanel = n.panels[i]
+	}
+}
+
+func (d *DockArea) GrabsPointer() bool {
+	return d.pressed
+}
+
+func (d *DockArea) FindWidgetAt(pos q2d.Point) Widget {
+	if !d.Rect.Contains(pos) {
+		return nil
+	}
+	if d.root != nil {
+		if w := d.root.widget().FindWidgetAt(pos); w != nil {
+			return w
+		}
+	}
+	return d
+}
+
+func (d *DockArea) Draw(img *q2d.Image) {
+	theme := d.GetTheme()
+	if theme == nil {
+		return
+	}
+
+	img.PushSubImage(d.Rect)
+	DrawBox(img, q2d.Rectangle{0, 0, d.Rect.Width(), d.Rect.Height()}, theme.Style(StyleInset, d.state()))
+	img.PopSubImage()
+
+	if d.root != nil {
+		d.root.widget().Draw(img)
+	}
+}
+
+// DockLayout is a serializable snapshot of the arrangement of a DockArea,
+// suitable for encoding with encoding/json.
+type DockLayout struct {
+	Root     *DockLayoutNode   `json:"root,omitempty"`
+	Floating []DockLayoutFloat `json:"floating,omitempty"`
+}
+
+// DockLayoutNode is either a split with two Children or a stack of Panels
+// referenced by ID.
+type DockLayoutNode struct {
+	Direction LayoutDirection   `json:"direction,omitempty"`
+	Ratio     float64           `json:"ratio,omitempty"`
+	Children  []*DockLayoutNode `json:"children,omitempty"`
+	Panels    []string          `json:"panels,omitempty"`
+	Active    int               `json:"active,omitempty"`
+}
+
+type DockLayoutFloat struct {
+	Panel string        `json:"panel"`
+	Rect  q2d.Rectangle `json:"rect"`
+}
+
+// SaveLayout returns a snapshot of the current arrangement.
+func (d *DockArea) SaveLayout() *DockLayout {
+	l := &DockLayout{}
+	if d.root != nil {
+		l.Root = saveDockNode(d.root)
+	}
+	for _, f := range d.floating {
+		l.Floating = append(l.Floating, DockLayoutFloat{
+			Panel: f.panel.ID,
+			Rect:  f.window.GetRect(),
+		})
+	}
+	return l
+}
+
+func saveDockNode(n *dockNode) *DockLayoutNode {
+	if n.isLeaf() {
+		ln := &DockLayoutNode{Active: n.tabs.ActiveTab}
+		for _, p := range n.panels {
+			ln.Panels = append(ln.Panels, p.ID)
+		}
+		return ln
+	}
+	ratio := 0.5
+	if total := n.splitter.Panes[0].Size + n.splitter.Panes[1].Size; total > 0 {
+		ratio = n.splitter.Panes[0].Size / total
+	}
+	return &DockLayoutNode{
+		Direction: n.splitter.Direction,
+		Ratio:     ratio,
+		Children:  []*DockLayoutNode{saveDockNode(n.children[0]), saveDockNode(n.children[1])},
+	}
+}
+
+// RestoreLayout replaces the current arrangement with l. All panels referenced
+// by l must have been registered, and floating panels need an OverlayManager.
+// The current arrangement is left untouched if l is invalid. Registered
+// panels not in l are closed.
+func (d *DockArea) RestoreLayout(l *DockLayout) error {
+	if len(l.Floating) > 0 && d.OverlayManager == nil {
+		return fmt.Errorf("qui: dock layout has floating panels but no overlay manager")
+	}
+	used := make(map[string]bool)
+	lookup := func(id string) (*DockPanel, error) {
+		p, ok := d.panels[id]
+		if !ok {
+			return nil, fmt.Errorf("qui: unknown dock panel %q", id)
+		}
+		if used[id] {
+			return nil, fmt.Errorf("qui: dock panel %q used more than once", id)
+		}
+		used[id] = true
+		return p, nil
+	}
+
+	var restore func(ln *DockLayoutNode) (*dockNode, error)
+	restore = func(ln *DockLayoutNode) (*dockNode, error) {
+		if len(ln.Children) > 0 {
+			if len(ln.Children) != 2 || len(ln.Panels) > 0 {
+				return nil, fmt.Errorf("qui: dock split must have exactly two children and no panels")
+			}
+			first, err := restore(ln.Children[0])
+			if err != nil {
+				return nil, err
+			}
+			second, err := restore(ln.Children[1])
+			if err != nil {
+				return nil, err
+			}
+			ratio := ln.Ratio
+			if ratio <= 0 || ratio >= 1 {
+				ratio = 0.5
+			}
+			return newDockSplit(ln.Direction, first, second, ratio), nil
+		}
+		if len(ln.Panels) == 0 {
+			return nil, fmt.Errorf("qui: dock stack has no panels")
+		}
+		var panels []*DockPanel
+		for _, id := range ln.Panels {
+			p, err := lookup(id)
+			if err != nil {
+				return nil, err
+			}
+			panels = append(panels, p)
+		}
+		n := newDockLeaf(panels...)
+		n.tabs.ActiveTab = ln.Active
+		n.syncTabs()
+		return n, nil
+	}
+
+	var root *dockNode
+	if l.Root != nil {
+		var err error
+		if root, err = restore(l.Root); err != nil {
+			return err
+		}
+	}
+	var floating []*DockPanel
+	for _, lf := range l.Floating {
+		p, err := lookup(lf.Panel)
+		if err != nil {
+			return err
+		}
+		floating = append(floating, p)
+	}
+
+	for len(d.floating) > 0 {
+		d.closeFloat(d.floating[0])
+	}
+	d.root = root
+	for i, p := range floating {
+		d.FloatPanel(p, l.Floating[i].Rect)
+	}
+	for id, p := range d.panels {
+		if !used[id] && p.Content != nil {
+			setParent(p.Content, nil)
+		}
+	}
+	d.Layout(Size{d.Rect.Width(), d.Rect.Height()})
+	return nil
+}
+
+// dockPreview is the overlay showing drop indicators during a panel drag.
+type dockPreview struct {
+	BaseWidget
+	area    *DockArea
+	targets []dockTarget
+	active  int
+}
+
+func (p *dockPreview) FindWidgetAt(pos q2d.Point) Widget {
+	return nil
+}
+
+func (p *dockPreview) Draw(img *q2d.Image) {
+	theme := p.area.GetTheme()
+	if theme == nil {
+		return
+	}
+
+	if p.active >= 0 && p.active < len(p.targets) {
+		DrawBox(img, p.area.targetRect(p.targets[p.active]), theme.Style(StyleDropTarget, StateHovered))
+	}
+
+	for i, t := range p.targets {
+		var state StyleState
+		if i == p.active {
+			state = StateSelected
+		}
+		st := theme.Style(StyleDropTarget, state)
+		DrawBox(img, t.rect, st)
+
+		icon := IconMaximize
+		switch t.side {
+		case DockLeft:
+			icon = IconArrowLeft
+		case DockRight:
+			icon = IconArrowRight
+		case DockTop:
+			icon = IconArrowUp
+		case DockBottom:
+			icon = IconArrowDown
+		}
+		off := (t.rect.Width() - theme.IconSize()) / 2
+		theme.DrawIcon(img, icon, q2d.Point{t.rect.X() + off, t.rect.Y() + off}, st.Text)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
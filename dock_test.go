@@ -0,0 +1,129 @@
+package qui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qbradq/q2d"
+	"golang.org/x/image/font/basicfont"
+)
+
+var dockPanelIDs = []string{"editor", "files", "outline", "properties", "console", "search", "hidden"}
+
+// newTestDockArea returns a laid out DockArea shown by a Master with all of
+// dockPanelIDs registered but none placed.
+func newTestDockArea() (*DockArea, map[string]*DockPanel) {
+	d := NewDockArea()
+	m := NewMaster(d, GenerateThemeFromColor(q2d.Color{0, 120, 215, 255}, basicfont.Face7x13))
+	m.Layout(Size{800, 600})
+	d.OverlayManager = m
+	panels := make(map[string]*DockPanel)
+	for _, id := range dockPanelIDs {
+		p := NewDockPanel(id, id, NewLabel(id))
+		panels[id] = p
+		d.RegisterPanel(p)
+	}
+	return d, panels
+}
+
+// arrange places the panels of a newTestDockArea.
+func arrange(d *DockArea, panels map[string]*DockPanel) {
+	d.AddPanel(panels["editor"], DockCenter)
+	d.AddPanel(panels["files"], DockLeft)
+	d.AddPanel(panels["outline"], DockRight)
+	d.AddPanelBeside(panels["properties"], panels["outline"], DockCenter)
+	d.AddPanel(panels["console"], DockBottom)
+	d.FloatPanel(panels["search"], q2d.Rectangle{100, 120, 300, 200})
+	d.Layout(Size{800, 600})
+}
+
+func marshalLayout(t *testing.T, d *DockArea) string {
+	t.Helper()
+	buf, err := json.Marshal(d.SaveLayout())
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(buf)
+}
+
+func TestDockLayoutRoundTrip(t *testing.T) {
+	d, panels := newTestDockArea()
+	arrange(d, panels)
+	n, _ := d.findPanel(panels["properties"])
+	n.tabs.ActiveTab = 1
+	saved := marshalLayout(t, d)
+
+	other, otherPanels := newTestDockArea()
+	other.AddPanel(otherPanels["hidden"], DockCenter)
+	var l DockLayout
+	if err := json.Unmarshal([]byte(saved), &l); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if err := other.RestoreLayout(&l); err != nil {
+		t.Fatalf("RestoreLayout: %v", err)
+	}
+	if got := marshalLayout(t, other); got != saved {
+		t.Errorf("restored layout\n%s\nwant\n%s", got, saved)
+	}
+
+	for _, id := range dockPanelIDs {
+		content := otherPanels[id].Content
+		if id == "hidden" {
+			if parentOf(content) != nil {
+				t.Errorf("panel %q left out of the layout still has a parent", id)
+			}
+			continue
+		}
+		if parentOf(content) == nil {
+			t.Errorf("panel %q has no parent after RestoreLayout", id)
+		}
+	}
+	if n, i := other.findPanel(otherPanels["properties"]); n == nil || n.tabs.ActiveTab != 1 || i != 1 {
+		t.Errorf("properties tab not restored as the active tab 1 of its stack")
+	}
+	if len(other.floating) != 1 || other.floating[0].window.GetRect() != (q2d.Rectangle{100, 120, 300, 200}) {
+		t.Errorf("floating search panel not restored at its rectangle")
+	}
+
+	// Restoring the same layout again changes nothing
+	if err := other.RestoreLayout(&l); err != nil {
+		t.Fatalf("RestoreLayout again: %v", err)
+	}
+	if got := marshalLayout(t, other); got != saved {
+		t.Errorf("layout restored twice\n%s\nwant\n%s", got, saved)
+	}
+}
+
+func TestDockRestoreLayoutErrors(t *testing.T) {
+	leaf := func(ids ...string) *DockLayoutNode {
+		return &DockLayoutNode{Panels: ids}
+	}
+	tests := []struct {
+		name      string
+		layout    DockLayout
+		noManager bool
+	}{
+		{"unknown panel", DockLayout{Root: leaf("editor", "missing")}, false},
+		{"panel used twice", DockLayout{Root: &DockLayoutNode{Children: []*DockLayoutNode{leaf("editor"), leaf("editor")}}}, false},
+		{"floating panel also docked", DockLayout{Root: leaf("search"), Floating: []DockLayoutFloat{{Panel: "search"}}}, false},
+		{"unknown floating panel", DockLayout{Floating: []DockLayoutFloat{{Panel: "missing"}}}, false},
+		{"split with one child", DockLayout{Root: &DockLayoutNode{Children: []*DockLayoutNode{leaf("editor")}}}, false},
+		{"split with panels", DockLayout{Root: &DockLayoutNode{Children: []*DockLayoutNode{leaf("editor"), leaf("files")}, Panels: []string{"console"}}}, false},
+		{"empty stack", DockLayout{Root: &DockLayoutNode{}}, false},
+		{"floating without overlay manager", DockLayout{Root: leaf("editor"), Floating: []DockLayoutFloat{{Panel: "search"}}}, true},
+	}
+	for _, tt := range tests {
+		d, panels := newTestDockArea()
+		arrange(d, panels)
+		if tt.noManager {
+			d.OverlayManager = nil
+		}
+		before := marshalLayout(t, d)
+		if err := d.RestoreLayout(&tt.layout); err == nil {
+			t.Errorf("%s: RestoreLayout succeeded, want error", tt.name)
+		}
+		if got := marshalLayout(t, d); got != before {
+			t.Errorf("%s: failed RestoreLayout changed the layout to\n%s\nfrom\n%s", tt.name, got, before)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package qui
+
+import (
+	"image"
+
+	"github.com/qbradq/q2d"
+	"golang.org/x/image/font"
+)
+
+// DragDistance is how far in pixels the pointer has to move with the left
+// button held before a drag starts.
+var DragDistance = 4
+
+// Kinds of data dragged by the built-in widgets.
+const (
+	// DragKindListItem is dragged from a List. Value is the ListItem.
+	DragKindListItem = "qui/list-item"
+	// DragKindTab is dragged from a TabContainer. Value is the Tab.
+	DragKindTab = "qui/tab"
+)
+
+// DragData is the payload of a drag and drop operation.
+type DragData struct {
+	// Kind names the type of Value so that drop targets can tell whether they
+	// accept it, such as DragKindListItem or "text/plain".
+	Kind  string
+	Value any
+	// Source is the widget the drag started from.
+	Source Widget
+	// Image is drawn under the pointer during the drag. Without it a box with
+	// Icon and Text is drawn instead.
+	Image image.Image
+	Text  string
+	Icon  Icon
+	// Offset is added to the pointer position to place the top left corner of
+	// the drag image.
+	Offset q2d.Point
+	// Move is set by a drop target to ask the source to remove the dropped
+	// data, as when moving an item from one list to another.
+	Move bool
+}
+
+// DragSource is implemented by widgets that items can be dragged from.
+type DragSource interface {
+	// DragStart returns the data to drag when the left button was pressed at
+	// pos and the pointer moved away, or nil if there is nothing to drag.
+	DragStart(pos q2d.Point) *DragData
+	// DragEnd is called when the drag is over. accepted is true if the data
+	// was dropped on a target that took it.
+	DragEnd(data *DragData, accepted bool)
+}
+
+// DropTarget is implemented by widgets that accept dropped data.
+type DropTarget interface {
+	// DragAccept returns true if data can be dropped at pos.
+	DragAccept(data *DragData, pos q2d.Point) bool
+	// DragHover is called as data the target accepts is moved over it, and
+	// with over false when it moves away or the drag ends.
+	DragHover(data *DragData, pos q2d.Point, over bool)
+	// Drop is called when data is released over the target. It returns true
+	// if the data was taken.
+	Drop(data *DragData, pos q2d.Point) bool
+}
+
+// DragSourceFuncs is a DragSource built from functions, to make any widget a
+// drag source with EventHandlers.SetDragSource. Nil functions do nothing.
+type DragSourceFuncs struct {
+	OnStart func(pos q2d.Point) *DragData
+	OnEnd   func(data *DragData, accepted bool)
+}
+
+func (f *DragSourceFuncs) DragStart(pos q2d.Point) *DragData {
+	if f.OnStart == nil {
+		return nil
+	}
+	return f.OnStart(pos)
+}
+
+func (f *DragSourceFuncs) DragEnd(data *DragData, accepted bool) {
+	if f.OnEnd != nil {
+		f.OnEnd(data, accepted)
+	}
+}
+
+// DropTargetFuncs is a DropTarget built from functions, to make any widget a
+// drop target with EventHandlers.SetDropTarget. Without OnAccept nothing is
+// accepted.
+type DropTargetFuncs struct {
+	OnAccept func(data *DragData, pos q2d.Point) bool
+	OnHover  func(data *DragData, pos q2d.Point, over bool)
+	OnDrop   func(data *DragData, pos q2d.Point) bool
+}
+
+func (f *DropTargetFuncs) DragAccept(data *DragData, pos q2d.Point) bool {
+	return f.OnAccept != nil && f.OnAccept(data, pos)
+}
+
+func (f *DropTargetFuncs) DragHover(data *DragData, pos q2d.Point, over bool) {
+	if f.OnHover != nil {
+		f.OnHover(data, pos, over)
+	}
+}
+
+func (f *DropTargetFuncs) Drop(data *DragData, pos q2d.Point) bool {
+	return f.OnDrop != nil && f.OnDrop(data, pos)
+}
+
+// SetDragSource makes the widget a drag source. It takes precedence over the
+// widget's own DragSource implementation. Nil restores it.
+func (h *EventHandlers) SetDragSource(s DragSource) {
+	h.dragSource = s
+}
+
+// SetDropTarget makes the widget a drop target. It takes precedence over the
+// widget's own DropTarget implementation. Nil restores it.
+func (h *EventHandlers) SetDropTarget(t DropTarget) {
+	h.dropTarget = t
+}
+
+func dragSourceOf(w Widget) DragSource {
+	if h := handlersOf(w); h != nil && h.dragSource != nil {
+		return h.dragSource
+	}
+	s, _ := w.(DragSource)
+	return s
+}
+
+func dropTargetOf(w Widget) DropTarget {
+	if h := handlersOf(w); h != nil && h.dropTarget != nil {
+		return h.dropTarget
+	}
+	t, _ := w.(DropTarget)
+	return t
+}
+
+// dragState is the drag in progress.
+type dragState struct {
+	data   *DragData
+	source DragSource
+	target DropTarget // Target under the pointer that accepts the data
+	pos    q2d.Point
+}
+
+// StartDrag starts dragging data from code, such as from a mouse handler.
+// If data.Source is a drag source it is told when the drag ends.
+func (m *Master) StartDrag(data *DragData) {
+	if m.drag != nil {
+		m.endDrag(m.MousePos, false)
+	}
+	m.drag = &dragState{
+		data: data,
+		pos:  m.MousePos,
+	}
+	if data.Source != nil {
+		m.drag.source = dragSourceOf(data.Source)
+	}
+	// The release ends the drag rather than clicking
+	m.pressTarget = nil
+	m.dragPending = false
+	m.longPressArmed = false
+	m.ReleasePointer()
+	m.updateDropTarget(m.MousePos)
+}
+
+// Dragging returns the data being dragged, or nil.
+func (m *Master) Dragging() *DragData {
+	if m.drag == nil {
+		return nil
+	}
+	return m.drag.data
+}
+
+// checkDrag starts a drag once the pointer has moved far enough from where the
+// left button was pressed on a drag source.
+func (m *Master) checkDrag(pos q2d.Point) {
+	if !m.dragPending || m.pressButton != MouseButtonLeft {
+		return
+	}
+	d := pos.Sub(m.pressPos)
+	if dist := m.px(DragDistance); abs(d.X()) <= dist && abs(d.Y()) <= dist {
+		return
+	}
+	m.dragPending = false
+	for w := m.pressTarget; w != nil; w = parentOf(w) {
+		// Widgets tracking a drag of their own keep it
+		if m.capture != nil && m.capture != w {
+			continue
+		}
+		s := dragSourceOf(w)
+		if s == nil || !interactive(w) {
+			continue
+		}
+		if data := s.DragStart(m.pressPos); data != nil {
+			if data.Source == nil {
+				data.Source = w
+			}
+			m.StartDrag(data)
+			m.drag.source = s
+			return
+		}
+	}
+}
+
+// dropTargetAt returns the innermost drop target at pos that accepts data.
+func (m *Master) dropTargetAt(pos q2d.Point, data *DragData) DropTarget {
+	w := m.widgetAt(pos)
+	if !interactive(w) {
+		return nil
+	}
+	for ; w != nil; w = parentOf(w) {
+		if t := dropTargetOf(w); t != nil && t.DragAccept(data, pos) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (m *Master) updateDropTarget(pos q2d.Point) {
+	d := m.drag
+	d.pos = pos
+	t := m.dropTargetAt(pos, d.data)
+	if d.target != nil && d.target != t {
+		d.target.DragHover(d.data, pos, false)
+	}
+	d.target = t
+	if t != nil {
+		t.DragHover(d.data, pos, true)
+	}
+}
+
+// endDrag drops the dragged data at pos, or cancels the drag if drop is false.
+func (m *Master) endDrag(pos q2d.Point, drop bool) {
+	d := m.drag
+	m.drag = nil
+	accepted := false
+	if d.target != nil {
+		d.target.DragHover(d.data, pos, false)
+		if drop {
+			accepted = d.target.Drop(d.data, pos)
+		}
+	}
+	if d.source != nil {
+		d.source.DragEnd(d.data, accepted)
+	}
+	m.UpdateHover(pos)
+}
+
+// dragEvent handles e while dragging. It returns true if e was consumed.
+func (m *Master) dragEvent(e Event) bool {
+	switch evt := e.(type) {
+	case MouseEvent:
+		switch evt.TypeVal {
+		case EventMouseMove:
+			m.updateDropTarget(evt.Pos)
+		case EventMouseUp:
+			m.endDrag(evt.Pos, true)
+		}
+		return true
+	case KeyEvent:
+		if evt.TypeVal == EventKeyDown && evt.Key == KeyEscape {
+			m.endDrag(m.MousePos, false)
+			return true
+		}
+	}
+	return false
+}
+
+// drawDrag draws the drag image under the pointer.
+func (m *Master) drawDrag(img *q2d.Image) {
+	d := m.drag.data
+	p := m.drag.pos.Add(d.Offset)
+	if d.Image != nil {
+		img.DrawImageScaled(d.Image, p, 1)
+		return
+	}
+
+	theme := m.currentTheme()
+	if d.Source != nil {
+		theme = d.Source.GetTheme()
+	}
+	if theme == nil || theme.Font == nil {
+		return
+	}
+	st := theme.Style(StyleDrag, 0)
+	metrics := theme.Font.Metrics()
+	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	h := max(textHeight, theme.IconSize()) + st.Padding.Top + st.Padding.Bottom + 2
+	w := font.MeasureString(theme.Font, d.Text).Ceil() + st.Padding.Left + st.Padding.Right
+	if d.Icon != IconNone {
+		w += theme.IconSize() + theme.Spacing
+	}
+
+	r := q2d.Rectangle{p.X(), p.Y(), w, h}
+	img.PushSubImage(r)
+	DrawBox(img, q2d.Rectangle{0, 0, w, h}, st)
+	x := st.Padding.Left
+	if d.Icon != IconNone {
+		theme.DrawIcon(img, d.Icon, q2d.Point{x, (h - theme.IconSize()) / 2}, st.Text)
+		x += theme.IconSize() + theme.Spacing
+	}
+	img.Text(q2d.Point{x, (h - textHeight) / 2}, st.Text, theme.Font, false, "%s", d.Text)
+	img.PopSubImage()
+}
@@ -0,0 +1,183 @@
+package qui
+
+import (
+	"math"
+
+	"github.com/qbradq/q2d"
+)
+
+// Shadow is a drop shadow. It is not drawn if Color has no alpha.
+type Shadow struct {
+	Color q2d.Color
+	// OffsetX and OffsetY move the shadow from under the box that casts it.
+	OffsetX, OffsetY int
+	// Blur is how many pixels the edge of the shadow fades out over.
+	Blur int
+}
+
+// corners selects the corners DrawBox rounds.
+type corners int
+
+const (
+	cornersTop corners = 1 << iota
+	cornersBottom
+	cornersAll = cornersTop | cornersBottom
+)
+
+// DrawBox draws the background and border of st in r, relative to the
+// current sub image, with corners rounded by st.Radius. If st.Gradient is
+// set the background is a vertical linear gradient from st.Background at
+// the top to st.Gradient at the bottom. A background with no alpha is not
+// drawn, and translucent colors are blended over what is already drawn.
+func DrawBox(img *q2d.Image, r q2d.Rectangle, st Style) {
+	drawBox(img, r, st, cornersAll)
+}
+
+// drawBox is DrawBox rounding only some corners, as for tabs.
+func drawBox(img *q2d.Image, r q2d.Rectangle, st Style, c corners) {
+	radius := min(max(st.Radius, 0), r.Width()/2, r.Height()/2)
+	bw := max(st.BorderWidth, 0)
+	if st.Border.A() == 0 {
+		bw = 0
+	}
+	inner := q2d.Rectangle{r.X() + bw, r.Y() + bw, r.Width() - 2*bw, r.Height() - 2*bw}
+	innerRadius := max(radius-bw, 0)
+	gradient := st.Gradient.A() > 0
+	if (st.Background.A() > 0 || gradient) && inner.Width() > 0 && inner.Height() > 0 {
+		for y := 0; y < inner.Height(); y++ {
+			bg := st.Background
+			if gradient && inner.Height() > 1 {
+				bg = lerpColor(st.Background, st.Gradient, float64(y)/float64(inner.Height()-1))
+			}
+			x1, x2 := roundedSpan(inner, innerRadius, y, c)
+			fillSpan(img, inner.Y()+y, x1, x2, bg)
+		}
+	}
+	if bw == 0 {
+		return
+	}
+	for y := 0; y < r.Height(); y++ {
+		x1, x2 := roundedSpan(r, radius, y, c)
+		iy := y - bw
+		if iy < 0 || iy >= inner.Height() || inner.Width() <= 0 {
+			fillSpan(img, r.Y()+y, x1, x2, st.Border)
+			continue
+		}
+		ix1, ix2 := roundedSpan(inner, innerRadius, iy, c)
+		fillSpan(img, r.Y()+y, x1, ix1, st.Border)
+		fillSpan(img, r.Y()+y, ix2, x2, st.Border)
+	}
+}
+
+// fillSpan fills row y from x1 to x2 with c, blending it if it is
+// translucent.
+func fillSpan(img *q2d.Image, y, x1, x2 int, c q2d.Color) {
+	if c.A() == 255 {
+		img.HLine(y, x1, x2, 1, c)
+		return
+	}
+	for x := x1; x < x2; x++ {
+		blendPixel(img, q2d.Point{x, y}, c, 1)
+	}
+}
+
+// roundedSpan returns the horizontal extent of row y of r with the corners c
+// rounded by radius.
+func roundedSpan(r q2d.Rectangle, radius, y int, c corners) (int, int) {
+	inset := 0
+	if radius > 0 {
+		var dy float64
+		if y < radius && c&cornersTop != 0 {
+			dy = float64(radius) - float64(y) - 0.5
+		} else if y >= r.Height()-radius && c&cornersBottom != 0 {
+			dy = float64(y-(r.Height()-radius)) + 0.5
+		}
+		if dy > 0 {
+			inset = radius - int(math.Round(math.Sqrt(float64(radius*radius)-dy*dy)))
+		}
+	}
+	return r.X() + inset, r.X() + r.Width() - inset
+}
+
+// DrawShadow draws s as cast by a box in r with corners rounded by radius,
+// relative to the current sub image. Nothing is drawn under the box itself,
+// so the box is drawn over the shadow afterwards.
+func DrawShadow(img *q2d.Image, r q2d.Rectangle, radius int, s Shadow) {
+	if s.Color.A() == 0 || r.Width() <= 0 || r.Height() <= 0 {
+		return
+	}
+	radius = min(max(radius, 0), r.Width()/2, r.Height()/2)
+	sr := q2d.Rectangle{r.X() + s.OffsetX, r.Y() + s.OffsetY, r.Width(), r.Height()}
+	blur := max(s.Blur, 0)
+	grow := blur/2 + 1
+	for y := sr.Y() - grow; y < sr.Y()+sr.Height()+grow; y++ {
+		for x := sr.X() - grow; x < sr.X()+sr.Width()+grow; x++ {
+			if ry := y - r.Y(); ry >= 0 && ry < r.Height() {
+				if x1, x2 := roundedSpan(r, radius, ry, cornersAll); x >= x1 && x < x2 {
+					continue
+				}
+			}
+			d := roundedRectDistance(sr, radius, float64(x)+0.5, float64(y)+0.5)
+			var a float64
+			if blur == 0 {
+				if d <= 0 {
+					a = 1
+				}
+			} else {
+				a = min(max(0.5-d/float64(blur), 0), 1)
+				a = a * a * (3 - 2*a)
+			}
+			if a > 0 {
+				blendPixel(img, q2d.Point{x, y}, s.Color, a)
+			}
+		}
+	}
+}
+
+// roundedRectDistance returns how far the point x, y is outside of r with
+// corners rounded by radius, negative inside of it.
+func roundedRectDistance(r q2d.Rectangle, radius int, x, y float64) float64 {
+	hw, hh := float64(r.Width())/2, float64(r.Height())/2
+	rad := float64(radius)
+	qx := math.Abs(x-(float64(r.X())+hw)) - (hw - rad)
+	qy := math.Abs(y-(float64(r.Y())+hh)) - (hh - rad)
+	outside := math.Hypot(math.Max(qx, 0), math.Max(qy, 0))
+	return outside + math.Min(math.Max(qx, qy), 0) - rad
+}
+
+// BlendRect alpha-blends c over the pixels of r, relative to the current
+// sub image.
+func BlendRect(img *q2d.Image, r q2d.Rectangle, c q2d.Color) {
+	for y := r.Y(); y < r.Y()+r.Height(); y++ {
+		for x := r.X(); x < r.X()+r.Width(); x++ {
+			blendPixel(img, q2d.Point{x, y}, c, 1)
+		}
+	}
+}
+
+// blendPixel blends c over the pixel at p with the alpha of c multiplied by
+// coverage.
+func blendPixel(img *q2d.Image, p q2d.Point, c q2d.Color, coverage float64) {
+	a := float64(c.A()) / 255.0 * coverage
+	bg := img.At(p)
+	img.Set(p, q2d.Color{
+		uint8(float64(c.R())*a + float64(bg.R())*(1-a)),
+		uint8(float64(c.G())*a + float64(bg.G())*(1-a)),
+		uint8(float64(c.B())*a + float64(bg.B())*(1-a)),
+		uint8(255*a + float64(bg.A())*(1-a)),
+	})
+}
+
+// withAlpha returns c with its alpha set to a.
+func withAlpha(c q2d.Color, a uint8) q2d.Color {
+	return q2d.Color{c.R(), c.G(), c.B(), a}
+}
+
+// lerpColor returns the color p of the way from a to b.
+func lerpColor(a, b q2d.Color, p float64) q2d.Color {
+	var c q2d.Color
+	for i := range c {
+		c[i] = uint8(float64(a[i]) + (float64(b[i])-float64(a[i]))*p + 0.5)
+	}
+	return c
+}
@@ -12,9 +12,11 @@ type Entry struct {
 }
 
 func NewEntry(initialText string, t EntryType) *Entry {
-	return &Entry{
+	e := &Entry{
 		Input: NewTextInput(initialText, t),
 	}
+	adopt(e, e.Input)
+	return e
 }
 
 func (e *Entry) MinSize() Size {
@@ -39,7 +41,8 @@ func (e *Entry) MinSize() Size {
 
 	height := inputSize.Height
 
-	return Size{width + theme.Padding.Left + theme.Padding.Right, height + theme.Padding.Top + theme.Padding.Bottom}
+	pad := theme.Style(StyleInput, 0).Padding
+	return Size{width + pad.Left + pad.Right, height + pad.Top + pad.Bottom}
 }
 
 func (e *Entry) Event(evt Event) bool {
@@ -54,6 +57,7 @@ func (e *Entry) Event(evt Event) bool {
 				// If FindWidgetAt returns e.Input, then e.Input gets focus.
 				// If FindWidgetAt returns e, then e gets focus.
 				// e.Input is internal.
+				e.Input.selectAt(event.Pos, event.Clicks)
 				return true
 			}
 		}
@@ -77,15 +81,13 @@ func (e *Entry) Draw(img *q2d.Image) {
 		return
 	}
 
-	img.PushSubImage(e.Rect)
-
-	bgColor := theme.BackgroundColor.Darken(0.1)
+	state := e.state()
 	if e.Input.focused {
-		bgColor = theme.BackgroundColor.Lighten(0.1)
+		state |= StateFocused
 	}
-
-	img.Fill(bgColor)
-	img.Border(theme.BorderColor)
+	st := theme.Style(StyleInput, state)
+	img.PushSubImage(e.Rect)
+	DrawBox(img, q2d.Rectangle{0, 0, e.Rect.Width(), e.Rect.Height()}, st)
 	img.PopSubImage()
 
 	// Update Input rect to be inside padding
@@ -95,10 +97,10 @@ func (e *Entry) Draw(img *q2d.Image) {
 	// e.Rect is absolute.
 
 	inputRect := q2d.Rectangle{
-		e.Rect.X() + theme.Padding.Left,
-		e.Rect.Y() + theme.Padding.Top,
-		e.Rect.Width() - (theme.Padding.Left + theme.Padding.Right),
-		e.Rect.Height() - (theme.Padding.Top + theme.Padding.Bottom),
+		e.Rect.X() + st.Padding.Left,
+		e.Rect.Y() + st.Padding.Top,
+		e.Rect.Width() - (st.Padding.Left + st.Padding.Right),
+		e.Rect.Height() - (st.Padding.Top + st.Padding.Bottom),
 	}
 	e.Input.Rect = inputRect
 
@@ -12,16 +12,61 @@ const (
 	EventKeyUp
 	EventTextInput
 	EventScroll
+	// EventClick is sent as a MouseEvent after a button is pressed and
+	// released over the same widget.
+	EventClick
+	// EventFocus and EventBlur are sent as a FocusEvent when a widget gains or
+	// loses the keyboard focus.
+	EventFocus
+	EventBlur
+	// EventMouseEnter and EventMouseLeave are sent as a MouseEvent to a widget
+	// and its ancestors when the pointer moves onto or off them. They do not
+	// bubble.
+	EventMouseEnter
+	EventMouseLeave
+	// EventLongPress is sent as a MouseEvent when a button is held without
+	// moving for LongPressTime. No click follows the release.
+	EventLongPress
+	// EventTouchBegin, EventTouchMove, EventTouchEnd and EventTouchCancel are
+	// sent as a TouchEvent for each finger on a touch screen.
+	EventTouchBegin
+	EventTouchMove
+	EventTouchEnd
+	EventTouchCancel
+	// EventTap, EventPan, EventPinch and EventSwipe are sent as a
+	// GestureEvent when Master recognizes a gesture in the touch events.
+	EventTap
+	EventPan
+	EventPinch
+	EventSwipe
+	// EventNavigate is sent as a NavEvent for directional navigation.
+	EventNavigate
+	// EventComposition is sent as a CompositionEvent while an input method
+	// composes text.
+	EventComposition
+	// EventThemeChange is sent as a ThemeEvent to the handlers of every widget
+	// when Master.SetTheme changes the theme or Master.SetScale the scale. It
+	// does not bubble.
+	EventThemeChange
 )
 
 type Event interface {
 	Type() EventType
 }
 
+const (
+	MouseButtonLeft = iota
+	MouseButtonRight
+	MouseButtonMiddle
+)
+
 type MouseEvent struct {
 	TypeVal EventType
 	Pos     q2d.Point
 	Button  int // 0: Left, 1: Right, 2: Middle
+	// Clicks is 1 for a single click, 2 for a double-click and so on. It is
+	// filled in by Master for presses, releases and clicks.
+	Clicks int
 }
 
 func (e MouseEvent) Type() EventType { return e.TypeVal }
@@ -34,27 +79,135 @@ type ScrollEvent struct {
 
 func (e ScrollEvent) Type() EventType { return e.TypeVal }
 
+// TouchEvent reports a finger touching, moving on or leaving the screen.
+type TouchEvent struct {
+	TypeVal EventType
+	// ID tells the fingers apart. It stays the same from EventTouchBegin
+	// until EventTouchEnd or EventTouchCancel.
+	ID  int
+	Pos q2d.Point
+}
+
+func (e TouchEvent) Type() EventType { return e.TypeVal }
+
+// GestureState is the progress of a continuous gesture.
+type GestureState int
+
+const (
+	GestureBegan GestureState = iota
+	GestureChanged
+	// GestureEnded is also the state of taps and swipes.
+	GestureEnded
+	// GestureCancelled is sent instead of GestureEnded when the touches
+	// were cancelled.
+	GestureCancelled
+)
+
+// SwipeDirection is the direction of a swipe gesture.
+type SwipeDirection int
+
+const (
+	SwipeLeft SwipeDirection = iota
+	SwipeRight
+	SwipeUp
+	SwipeDown
+)
+
+// GestureEvent reports a gesture made of touch events.
+type GestureEvent struct {
+	TypeVal EventType
+	State   GestureState
+	// Pos is the position of the finger, or the point between both fingers
+	// of a pinch.
+	Pos q2d.Point
+	// Delta is how far Pos moved since the previous event of the gesture.
+	Delta q2d.Point
+	// Scale is the change of the distance between the fingers of a pinch
+	// since the previous event, 1 meaning no change.
+	Scale float64
+	// VelocityX and VelocityY are the speed of the finger in pixels per
+	// second when a pan ends or a swipe is made.
+	VelocityX float64
+	VelocityY float64
+	Direction SwipeDirection
+}
+
+func (e GestureEvent) Type() EventType { return e.TypeVal }
+
+// KeyMod is a set of modifier keys held during a key event.
+type KeyMod int
+
+const (
+	ModShift KeyMod = 1 << iota
+	ModCtrl
+	ModAlt
+	ModSuper
+)
+
 type KeyEvent struct {
 	TypeVal EventType
 	Key     int // Ebiten key code or similar
+	Mods    KeyMod
 }
 
 func (e KeyEvent) Type() EventType { return e.TypeVal }
 
+type FocusEvent struct {
+	TypeVal EventType
+}
+
+func (e FocusEvent) Type() EventType { return e.TypeVal }
+
 type TextInputEvent struct {
 	Text string
 }
 
 func (e TextInputEvent) Type() EventType { return EventTextInput }
 
+// CompositionEvent carries the text an input method is composing, known as
+// preedit text, before it is committed with a TextInputEvent. Text widgets
+// show it at the cursor until then.
+type CompositionEvent struct {
+	// Text is the preedit text. Empty text ends the composition.
+	Text string
+	// Cursor is the position of the caret in Text, in runes.
+	Cursor int
+	// SelStart and SelEnd are the range of Text in runes the input method is
+	// converting, drawn with a thicker underline. They are equal if there is
+	// none.
+	SelStart int
+	SelEnd   int
+}
+
+func (e CompositionEvent) Type() EventType { return EventComposition }
+
+// ThemeEvent tells of a change of the theme of a Master. Old and New are
+// Master.Theme before and after, which are the same for a change of scale.
+type ThemeEvent struct {
+	Old, New *Theme
+}
+
+func (e ThemeEvent) Type() EventType { return EventThemeChange }
+
 const (
 	KeyBackspace = 8
+	KeyTab       = 9
 	KeyEnter     = 13
+	KeyShift     = 16
+	KeyCtrl      = 17
+	KeyAlt       = 18
+	KeyEscape    = 27
+	KeySpace     = 32
+	KeyPageUp    = 33
+	KeyPageDown  = 34
 	KeyHome      = 36
 	KeyLeft      = 37
 	KeyUp        = 38
 	KeyRight     = 39
 	KeyDown      = 40
 	KeyEnd       = 35
+	KeyInsert    = 45
 	KeyDelete    = 46
+	KeySuper     = 91
+	KeyF1        = 112 // F2 to F12 follow in order
 )
@@ -3,6 +3,7 @@ module github.com/qbradq/qui
 go 1.25
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/qbradq/q2d v0.0.0-20251129230231-f407b354fc1d
 	golang.org/x/image v0.33.0
 )
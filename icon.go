@@ -57,9 +57,17 @@ const (
 	IconsPerRow   = IconSheetSize / IconSize
 )
 
-// DrawIcon draws the icon at the given position with the given color (tint).
+// DrawIcon draws the icon at the given position with the given color (tint)
+// from the icon sheet of DefaultTheme. Widgets use Theme.DrawIcon.
 func DrawIcon(img *q2d.Image, icon Icon, p q2d.Point, c q2d.Color) {
-	if DefaultTheme == nil || DefaultTheme.IconSheet == nil || icon == IconNone {
+	DefaultTheme.DrawIcon(img, icon, p, c)
+}
+
+// DrawIcon draws the icon at the given position with the given color (tint)
+// from the theme's icon sheet, at Theme.IconSize. When the theme is scaled up
+// IconSheet2x is used if set.
+func (t *Theme) DrawIcon(img *q2d.Image, icon Icon, p q2d.Point, c q2d.Color) {
+	if t == nil || t.IconSheet == nil || icon == IconNone {
 		return
 	}
 
@@ -68,56 +76,31 @@ func DrawIcon(img *q2d.Image, icon Icon, p q2d.Point, c q2d.Color) {
 		return
 	}
 
-	sx := (idx % IconsPerRow) * IconSize
-	sy := (idx / IconsPerRow) * IconSize
-
-	// We need to draw a sub-image of the icon sheet, tinted with c.
-	// q2d doesn't support complex blending/tinting of images easily yet,
-	// but the prompt says "The icon sheet will be white and transparent to allow tinted rendering".
-	// So we can iterate pixels and multiply alpha/color.
-
-	// Since q2d.Image is our target, and IconSheet is likely an image.Image or q2d.Image.
-	// Let's assume IconSheet is *image.RGBA for now in the Theme, or *q2d.Image.
-	// If it's *q2d.Image, we can read from it.
-
-	// Let's implement a blit with tint.
-
-	// destRect := q2d.Rectangle{p.X(), p.Y(), IconSize, IconSize}
-
-	// Check clip
-	// This logic should probably be in q2d, but we can do it here for now.
-
-	sheet := DefaultTheme.IconSheet
-
-	for y := 0; y < IconSize; y++ {
-		for x := 0; x < IconSize; x++ {
-			srcC := sheet.At(q2d.Point{sx + x, sy + y})
-			if srcC.A() == 0 {
+	size := t.IconSize()
+	sheet := t.IconSheet
+	if t.IconSheet2x != nil && size > IconSize {
+		sheet = t.IconSheet2x
+	}
+	// Icons in a sheet are as large as the sheet is wide over IconsPerRow
+	cell := sheet.Rect.Width() / IconsPerRow
+	if cell == 0 {
+		return
+	}
+	sx := (idx % IconsPerRow) * cell
+	sy := (idx / IconsPerRow) * cell
+
+	// The icon sheet is white and transparent to allow tinted rendering, so
+	// only the alpha of the sheet is used. q2d.Image.Set does not blend, so
+	// the tinted pixels are blended by hand like q2d.Image.Text does.
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			pixelAlpha := iconAlpha(sheet, sx, sy, cell, size, x, y)
+			if pixelAlpha == 0 {
 				continue
 			}
 
-			// Tint: multiply alpha. Source is white (255,255,255,A).
-			// Target is (R,G,B, A * srcA).
-
-			// a := float64(srcC.A()) / 255.0
-			// finalA := float64(c.A()) / 255.0 * a
-
-			// Simple alpha blending with background is handled by q2d.Image.Set usually?
-			// q2d.Image.Set replaces? No, let's check q2d.Image.
-			// q2d.Image.Set just sets the pixel. It doesn't blend.
-			// q2d.Image.Text DOES blend.
-
-			// We should probably implement a DrawIcon or Blit in q2d, but I can't modify q2d easily without context switch.
-			// I'll implement manual blending here similar to Text.
-
 			targetP := p.Add(q2d.Point{x, y})
-			bg := img.At(targetP) // This respects clip?
-			// img.At checks clip and returns 0 if outside.
-			// But we need to know if it was actually outside or just transparent/black.
-			// Actually img.At returns Color{}, which is 0,0,0,0.
-
-			// We should check clip first.
-			// img.Set checks clip.
+			bg := img.At(targetP)
 
 			// Blending:
 			// outA = srcA + dstA(1-srcA)
@@ -126,7 +109,6 @@ func DrawIcon(img *q2d.Image, icon Icon, p q2d.Point, c q2d.Color) {
 			// In our case, "Source" is the tinted icon pixel.
 			// SrcR = c.R, SrcG = c.G, SrcB = c.B, SrcA = c.A * pixelAlpha
 
-			pixelAlpha := float64(srcC.A()) / 255.0
 			srcR := float64(c.R())
 			srcG := float64(c.G())
 			srcB := float64(c.B())
@@ -151,6 +133,24 @@ func DrawIcon(img *q2d.Image, icon Icon, p q2d.Point, c q2d.Color) {
 	}
 }
 
+// iconAlpha returns the alpha from 0 to 1 of pixel x, y of an icon drawn at
+// size from the cell of the sheet at sx, sy. The pixels of the cell that the
+// pixel covers are averaged.
+func iconAlpha(sheet *q2d.Image, sx, sy, cell, size, x, y int) float64 {
+	if cell == size {
+		return float64(sheet.At(q2d.Point{sx + x, sy + y}).A()) / 255.0
+	}
+	x1, y1 := x*cell/size, y*cell/size
+	x2, y2 := max((x+1)*cell/size, x1+1), max((y+1)*cell/size, y1+1)
+	sum := 0
+	for v := y1; v < y2; v++ {
+		for u := x1; u < x2; u++ {
+			sum += int(sheet.At(q2d.Point{sx + u, sy + v}).A())
+		}
+	}
+	return float64(sum) / float64((x2-x1)*(y2-y1)*255)
+}
+
 // CreateDummyIconSheet loads the embedded icons.png
 func CreateDummyIconSheet() *q2d.Image {
 	img, _, err := image.Decode(bytes.NewReader(iconsPng))
@@ -2,6 +2,7 @@ package qui
 
 import (
 	"image"
+	"math"
 
 	"github.com/qbradq/q2d"
 )
@@ -9,6 +10,18 @@ import (
 type ImageWidget struct {
 	BaseWidget
 	Img image.Image
+	// Zoomable images can be zoomed with a pinch and moved with a pan once
+	// zoomed in.
+	Zoomable bool
+	// Zoom is the scale the image is drawn at, 1 if zero.
+	Zoom float64
+	// MinZoom and MaxZoom limit pinch zooming, 0.25 and 8 if zero.
+	MinZoom float64
+	MaxZoom float64
+
+	offset   q2d.Point // Of the image's top left corner within the widget
+	pinching bool
+	panning  bool
 }
 
 func NewImageWidget(img image.Image) *ImageWidget {
@@ -23,6 +36,100 @@ func (w *ImageWidget) MinSize() Size {
 	return Size{b.Dx(), b.Dy()}
 }
 
+func (w *ImageWidget) zoom() float64 {
+	if w.Zoom <= 0 {
+		return 1
+	}
+	return w.Zoom
+}
+
+// ResetZoom shows the image at its own size again.
+func (w *ImageWidget) ResetZoom() {
+	w.Zoom = 1
+	w.offset = q2d.Point{}
+}
+
+// zoomAt changes the zoom keeping the pixel at pos, an absolute position, in
+// place.
+func (w *ImageWidget) zoomAt(pos q2d.Point, zoom float64) {
+	lo, hi := w.MinZoom, w.MaxZoom
+	if lo <= 0 {
+		lo = 0.25
+	}
+	if hi <= 0 {
+		hi = 8
+	}
+	zoom = math.Min(math.Max(zoom, lo), hi)
+	k := zoom / w.zoom()
+	rel := pos.Sub(q2d.Point{w.Rect.X(), w.Rect.Y()})
+	w.offset = q2d.Point{
+		rel.X() - int(math.Round(float64(rel.X()-w.offset.X())*k)),
+		rel.Y() - int(math.Round(float64(rel.Y()-w.offset.Y())*k)),
+	}
+	w.Zoom = zoom
+	w.clampOffset()
+}
+
+// clampOffset keeps a zoomed in image covering the widget. Images smaller
+// than the widget stay at its top left corner.
+func (w *ImageWidget) clampOffset() {
+	if w.Img == nil {
+		return
+	}
+	b := w.Img.Bounds()
+	sw := int(float64(b.Dx()) * w.zoom())
+	sh := int(float64(b.Dy()) * w.zoom())
+	x := min(max(w.offset.X(), w.Rect.Width()-sw), 0)
+	y := min(max(w.offset.Y(), w.Rect.Height()-sh), 0)
+	w.offset = q2d.Point{x, y}
+}
+
+func (w *ImageWidget) Event(e Event) bool {
+	g, ok := e.(GestureEvent)
+	if !ok || !w.Zoomable || w.Disabled {
+		return false
+	}
+	switch g.TypeVal {
+	case EventPinch:
+		if g.State == GestureBegan {
+			w.pinching = w.Rect.Contains(g.Pos)
+		}
+		if !w.pinching {
+			return false
+		}
+		if g.State == GestureChanged {
+			w.offset = w.offset.Add(g.Delta)
+			w.zoomAt(g.Pos, w.zoom()*g.Scale)
+		} else if g.State >= GestureEnded {
+			w.pinching = false
+		}
+		return true
+	case EventPan:
+		// Only a zoomed in image can be moved, otherwise the pan goes to
+		// the parent, such as a ScrolledContainer
+		if g.State == GestureBegan {
+			w.panning = w.Rect.Contains(g.Pos) && w.zoom() > 1
+		}
+		if !w.panning {
+			return false
+		}
+		w.offset = w.offset.Add(g.Delta)
+		w.clampOffset()
+		if g.State >= GestureEnded {
+			w.panning = false
+		}
+		return true
+	}
+	return false
+}
+
+func (w *ImageWidget) FindWidgetAt(pos q2d.Point) Widget {
+	if w.Rect.Contains(pos) {
+		return w
+	}
+	return nil
+}
+
 func (w *ImageWidget) Draw(img *q2d.Image) {
 	if w.Img == nil {
 		return
@@ -38,18 +145,26 @@ func (w *ImageWidget) Draw(img *q2d.Image) {
 	b := w.Img.Bounds()
 	wW, wH := w.Rect.Width(), w.Rect.Height()
 
-	// Draw 1:1 for now, clipped to widget rect
-
+	// Nearest neighbour scaling by the zoom, clipped to widget rect
+	z := w.zoom()
 	for y := 0; y < wH; y++ {
-		if y >= b.Dy() {
+		sy := int(math.Floor(float64(y-w.offset.Y()) / z))
+		if sy < 0 {
+			continue
+		}
+		if sy >= b.Dy() {
 			break
 		}
 		for x := 0; x < wW; x++ {
-			if x >= b.Dx() {
+			sx := int(math.Floor(float64(x-w.offset.X()) / z))
+			if sx < 0 {
+				continue
+			}
+			if sx >= b.Dx() {
 				break
 			}
 
-			r, g, b, a := w.Img.At(x+b.Min.X, y+b.Min.Y).RGBA()
+			r, g, b, a := w.Img.At(sx+b.Min.X, sy+b.Min.Y).RGBA()
 			// RGBA is 0-65535 premultiplied.
 			// q2d.Color expects uint8 non-premultiplied? Or whatever.
 			// Let's assume standard conversion.
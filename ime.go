@@ -0,0 +1,93 @@
+package qui
+
+import (
+	"github.com/qbradq/q2d"
+	"golang.org/x/image/font"
+)
+
+// CaretProvider is implemented by widgets that edit text. The Master reports
+// the caret of the focused one to the host so that the candidate window of an
+// input method opens next to it.
+type CaretProvider interface {
+	// CaretRect returns the absolute rectangle of the text caret, including
+	// any preedit text before it.
+	CaretRect() q2d.Rectangle
+}
+
+// CaretRect returns the caret rectangle of the focused widget. It returns
+// false if the focused widget does not edit text, in which case the host may
+// turn the input method off.
+func (m *Master) CaretRect() (q2d.Rectangle, bool) {
+	w, ok := m.FocusedWidget.(Widget)
+	if !ok || !interactive(w) {
+		return q2d.Rectangle{}, false
+	}
+	c, ok := w.(CaretProvider)
+	if !ok {
+		return q2d.Rectangle{}, false
+	}
+	return c.CaretRect(), true
+}
+
+// updateCaret calls OnCaretRect if the caret moved since the last call.
+func (m *Master) updateCaret() {
+	r, ok := m.CaretRect()
+	if r == m.caret && ok == m.caretShown {
+		return
+	}
+	m.caret, m.caretShown = r, ok
+	if m.OnCaretRect != nil {
+		m.OnCaretRect(r, ok)
+	}
+}
+
+// preedit is the composition state of a text widget.
+type preedit struct {
+	text     string
+	cursor   int
+	selStart int
+	selEnd   int
+}
+
+func (p *preedit) set(e CompositionEvent) {
+	n := len([]rune(e.Text))
+	p.text = e.Text
+	p.cursor = min(max(e.Cursor, 0), n)
+	p.selStart = min(max(e.SelStart, 0), n)
+	p.selEnd = min(max(e.SelEnd, p.selStart), n)
+}
+
+// draw draws the preedit text at p, relative to the current sub image, with
+// an underline and a thicker one below the range being converted.
+func (p *preedit) draw(img *q2d.Image, pos q2d.Point, f font.Face, c q2d.Color) {
+	if p.text == "" {
+		return
+	}
+	img.Text(pos, c, f, false, "%s", p.text)
+	runes := []rune(p.text)
+	metrics := f.Metrics()
+	y := pos.Y() + (metrics.Ascent + metrics.Descent).Ceil() - 1
+	w := font.MeasureString(f, p.text).Ceil()
+	img.HLine(y, pos.X(), pos.X()+w, 1, c)
+	if p.selEnd > p.selStart {
+		x1 := pos.X() + font.MeasureString(f, string(runes[:p.selStart])).Ceil()
+		x2 := pos.X() + font.MeasureString(f, string(runes[:p.selEnd])).Ceil()
+		img.HLine(y-1, x1, x2, 2, c)
+	}
+}
+
+// cursorX returns the offset of the caret within the preedit text.
+func (p *preedit) cursorX(f font.Face) int {
+	return font.MeasureString(f, string([]rune(p.text)[:p.cursor])).Ceil()
+}
+
+// wrapText splits text into lines no wider than maxWidth the way q2d does
+// when drawing wrapped text.
+func wrapText(text string, f font.Face, maxWidth int) []string {
+	wrapped := wrapWords(text, f, maxWidth)
+	lines := make([]string, len(wrapped))
+	for i, l := range wrapped {
+		lines[i] = l.text(text)
+	}
+	return lines
+}
@@ -25,15 +25,22 @@ func (l *Label) MinSize() Size {
 	height := (metrics.Ascent + metrics.Descent).Ceil()
 
 	if l.Icon != IconNone {
-		width += IconSize + theme.Spacing
-		if IconSize > height {
-			height = IconSize
+		width += theme.IconSize() + theme.Spacing
+		if theme.IconSize() > height {
+			height = theme.IconSize()
 		}
 	}
 
 	return Size{width, height}
 }
 
+func (l *Label) FindWidgetAt(pos q2d.Point) Widget {
+	if l.Rect.Contains(pos) {
+		return l
+	}
+	return nil
+}
+
 func (l *Label) Draw(img *q2d.Image) {
 	theme := l.GetTheme()
 	if theme == nil {
@@ -54,10 +61,10 @@ func (l *Label) Draw(img *q2d.Image) {
 	}
 
 	if l.Icon != IconNone {
-		iconY := (l.Rect.Height() - IconSize) / 2
-		DrawIcon(img, l.Icon, q2d.Point{x, iconY}, theme.TextColor)
-		x += IconSize + theme.Spacing
+		iconY := (l.Rect.Height() - theme.IconSize()) / 2
+		theme.DrawIcon(img, l.Icon, q2d.Point{x, iconY}, l.textColor(theme))
+		x += theme.IconSize() + theme.Spacing
 	}
 
-	img.Text(q2d.Point{x, y}, theme.TextColor, theme.Font, true, "%s", l.Text)
+	img.Text(q2d.Point{x, y}, l.textColor(theme), theme.Font, true, "%s", l.Text)
 }
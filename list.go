@@ -10,8 +10,30 @@ type List struct {
 	Items         []ListItem
 	SelectedIndex int
 	OnSelect      func(index int)
+	// OnActivate is called when an item is double-clicked, or Enter is pressed
+	// while the list has focus.
+	OnActivate func(index int)
+	// ContextMenuFunc builds the context menu for a right-click on the item at
+	// index, or on the empty area below the items if index is -1.
+	ContextMenuFunc func(index int) *PopupMenu
+	// Reorderable lists let the user drag items to a new position, after
+	// which OnReorder is called with the old and new index of the item.
+	Reorderable bool
+	OnReorder   func(from, to int)
+	// DragItems lets items be dragged onto other widgets as DragData of kind
+	// DragKindListItem. If the drop target sets DragData.Move the item is
+	// removed from the list and OnDragOut is called with its former index.
+	DragItems bool
+	OnDragOut func(index int)
+	// AcceptDrop decides whether data dragged from elsewhere may be dropped
+	// before the item at index, or at the end if index is len(Items). OnDrop
+	// then takes the data.
+	AcceptDrop func(data *DragData, index int) bool
+	OnDrop     func(data *DragData, index int)
 
 	hoveredIndex int
+	dragIndex    int // Item being dragged from the list
+	dropIndex    int // Insertion point of a drag over the list, or -1
 	focused      bool
 	ScrollOffset int
 
@@ -26,6 +48,8 @@ func NewList(items []ListItem, onSelect func(index int)) *List {
 		SelectedIndex: -1,
 		OnSelect:      onSelect,
 		hoveredIndex:  -1,
+		dragIndex:     -1,
+		dropIndex:     -1,
 	}
 }
 
@@ -37,15 +61,15 @@ func (l *List) MinSize() Size {
 	maxWidth := 0
 	metrics := theme.Font.Metrics()
 	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
 	for _, item := range l.Items {
 		w := font.MeasureString(theme.Font, item.Text).Ceil()
 		if item.Icon != IconNone {
-			w += IconSize + theme.Spacing
+			w += theme.IconSize() + theme.Spacing
 		}
 		if w > maxWidth {
 			maxWidth = w
@@ -58,7 +82,8 @@ func (l *List) MinSize() Size {
 		h = len(l.Items)*lineHeight + 2
 	}
 
-	return Size{maxWidth + theme.Padding.Left + theme.Padding.Right + 10, h} // Add space for scrollbar
+	pad := theme.Style(StyleListItem, 0).Padding
+	return Size{maxWidth + pad.Left + pad.Right + theme.Px(scrollBarSize), h} // Add space for scrollbar
 }
 
 func (l *List) Event(evt Event) bool {
@@ -68,12 +93,12 @@ func (l *List) Event(evt Event) bool {
 	}
 	metrics := theme.Font.Metrics()
 	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
-	barSize := 10
+	barSize := theme.Px(scrollBarSize)
 	contentHeight := len(l.Items) * lineHeight
 	viewportHeight := l.Rect.Height() - 2
 	maxScroll := contentHeight - viewportHeight
@@ -119,9 +144,14 @@ func (l *List) Event(evt Event) bool {
 					if l.OnSelect != nil {
 						l.OnSelect(index)
 					}
+					if event.Clicks == 2 && l.OnActivate != nil {
+						l.OnActivate(index)
+					}
 					return true
 				}
 			}
+		} else if event.TypeVal == EventMouseLeave {
+			l.hoveredIndex = -1
 		} else if event.TypeVal == EventMouseUp {
 			l.dragging = false
 			if l.Rect.Contains(event.Pos) {
@@ -132,8 +162,8 @@ func (l *List) Event(evt Event) bool {
 				deltaY := event.Pos.Y() - l.dragStart.Y()
 				trackH := viewportHeight
 				thumbH := int(float64(trackH) * float64(trackH) / float64(contentHeight))
-				if thumbH < 20 {
-					thumbH = 20
+				if thumbH < theme.Px(minThumbSize) {
+					thumbH = theme.Px(minThumbSize)
 				}
 
 				scrollDelta := int(float64(deltaY) * float64(maxScroll) / float64(trackH-thumbH))
@@ -197,12 +227,165 @@ func (l *List) Event(evt Event) bool {
 					}
 					return true
 				}
+			} else if event.Key == KeyEnter {
+				if l.SelectedIndex >= 0 && l.OnActivate != nil {
+					l.OnActivate(l.SelectedIndex)
+					return true
+				}
 			}
 		}
 	}
 	return false
 }
 
+// indexAt returns the index of the item at pos, or -1.
+func (l *List) indexAt(pos q2d.Point) int {
+	theme := l.GetTheme()
+	if theme == nil || theme.Font == nil || !l.Rect.Contains(pos) {
+		return -1
+	}
+	index := (pos.Y() - l.Rect.Y() - 1 + l.ScrollOffset) / l.lineHeight(theme)
+	if index < 0 || index >= len(l.Items) {
+		return -1
+	}
+	return index
+}
+
+func (l *List) lineHeight(theme *Theme) int {
+	metrics := theme.Font.Metrics()
+	return max((metrics.Ascent+metrics.Descent).Ceil(), theme.IconSize()) + theme.Px(2)
+}
+
+// insertIndexAt returns the index an item dropped at pos is inserted at.
+func (l *List) insertIndexAt(pos q2d.Point) int {
+	theme := l.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return len(l.Items)
+	}
+	lh := l.lineHeight(theme)
+	index := (pos.Y() - l.Rect.Y() - 1 + l.ScrollOffset + lh/2) / lh
+	return min(max(index, 0), len(l.Items))
+}
+
+// DragStart implements DragSource for Reorderable lists and lists with
+// DragItems set.
+func (l *List) DragStart(pos q2d.Point) *DragData {
+	if !l.Reorderable && !l.DragItems {
+		return nil
+	}
+	theme := l.GetTheme()
+	index := l.indexAt(pos)
+	if index < 0 || pos.X() >= l.Rect.X()+l.Rect.Width()-theme.Px(scrollBarSize) && len(l.Items)*l.lineHeight(theme) > l.Rect.Height()-2 {
+		// Nothing, or the scrollbar
+		return nil
+	}
+	l.dragIndex = index
+	item := l.Items[index]
+	top := q2d.Point{l.Rect.X() + 1, l.Rect.Y() + 1 + index*l.lineHeight(theme) - l.ScrollOffset}
+	return &DragData{
+		Kind:   DragKindListItem,
+		Value:  item,
+		Source: l,
+		Text:   item.Text,
+		Icon:   item.Icon,
+		Offset: top.Sub(pos),
+	}
+}
+
+func (l *List) DragEnd(data *DragData, accepted bool) {
+	index := l.dragIndex
+	l.dragIndex = -1
+	if !accepted || !data.Move || index < 0 || index >= len(l.Items) {
+		return
+	}
+	l.Items = append(l.Items[:index], l.Items[index+1:]...)
+	if l.SelectedIndex == index {
+		l.SelectedIndex = -1
+	} else if l.SelectedIndex > index {
+		l.SelectedIndex--
+	}
+	if l.OnDragOut != nil {
+		l.OnDragOut(index)
+	}
+}
+
+// reordering returns true if data is an item of this list being reordered.
+func (l *List) reordering(data *DragData) bool {
+	return l.Reorderable && data.Source == l && l.dragIndex >= 0
+}
+
+func (l *List) DragAccept(data *DragData, pos q2d.Point) bool {
+	if l.reordering(data) {
+		return true
+	}
+	return data.Source != l && l.AcceptDrop != nil && l.AcceptDrop(data, l.insertIndexAt(pos))
+}
+
+func (l *List) DragHover(data *DragData, pos q2d.Point, over bool) {
+	l.dropIndex = -1
+	if over {
+		l.dropIndex = l.insertIndexAt(pos)
+	}
+}
+
+func (l *List) Drop(data *DragData, pos q2d.Point) bool {
+	to := l.insertIndexAt(pos)
+	if !l.reordering(data) {
+		if l.OnDrop != nil {
+			l.OnDrop(data, to)
+		}
+		return true
+	}
+
+	from := l.dragIndex
+	if to > from {
+		to-- // Removing the item shifts the insertion point up
+	}
+	if to == from {
+		return true
+	}
+	item := l.Items[from]
+	l.Items = append(l.Items[:from], l.Items[from+1:]...)
+	l.Items = append(l.Items[:to], append([]ListItem{item}, l.Items[to:]...)...)
+	// The selection follows the items
+	switch {
+	case l.SelectedIndex == from:
+		l.SelectedIndex = to
+	case from < l.SelectedIndex && l.SelectedIndex <= to:
+		l.SelectedIndex--
+	case to <= l.SelectedIndex && l.SelectedIndex < from:
+		l.SelectedIndex++
+	}
+	l.dragIndex = to
+	if l.OnReorder != nil {
+		l.OnReorder(from, to)
+	}
+	return true
+}
+
+// ContextMenuAt selects the item under pos and returns the menu built for it
+// by ContextMenuFunc. Without ContextMenuFunc the ContextMenu field is used.
+func (l *List) ContextMenuAt(pos q2d.Point) *PopupMenu {
+	if l.ContextMenuFunc == nil {
+		return l.ContextMenu
+	}
+	index := l.indexAt(pos)
+	if index >= 0 && index != l.SelectedIndex {
+		l.SelectedIndex = index
+		if l.OnSelect != nil {
+			l.OnSelect(index)
+		}
+	}
+	return l.ContextMenuFunc(index)
+}
+
+// Activate calls OnActivate with the selected item.
+func (l *List) Activate() {
+	if l.SelectedIndex >= 0 && l.OnActivate != nil {
+		l.OnActivate(l.SelectedIndex)
+	}
+}
+
 func (l *List) Focus() {
 	l.focused = true
 }
@@ -218,6 +401,10 @@ func (l *List) FindWidgetAt(pos q2d.Point) Widget {
 	return nil
 }
 
+func (l *List) GrabsPointer() bool {
+	return l.dragging
+}
+
 func (l *List) Draw(img *q2d.Image) {
 	theme := l.GetTheme()
 	if theme == nil {
@@ -227,23 +414,21 @@ func (l *List) Draw(img *q2d.Image) {
 	img.PushSubImage(l.Rect)
 	defer img.PopSubImage()
 
-	img.Fill(theme.BackgroundColor)
-
-	borderColor := theme.BorderColor
+	state := l.state()
 	if l.focused {
-		borderColor = theme.PrimaryColor
+		state |= StateFocused
 	}
-	img.Border(borderColor)
+	DrawBox(img, q2d.Rectangle{0, 0, l.Rect.Width(), l.Rect.Height()}, theme.Style(StyleList, state))
 
 	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	lineHeight := textHeight
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
-	barSize := 10
+	barSize := theme.Px(scrollBarSize)
 	viewportHeight := l.Rect.Height() - 2
 	contentHeight := len(l.Items) * lineHeight
 	maxScroll := contentHeight - viewportHeight
@@ -270,28 +455,30 @@ func (l *List) Draw(img *q2d.Image) {
 		item := l.Items[i]
 		y := i*lineHeight - l.ScrollOffset
 
-		bg := theme.BackgroundColor
+		itemState := l.state()
 		if i == l.SelectedIndex {
-			bg = theme.PrimaryColor
-		} else if i == l.hoveredIndex {
-			bg = theme.SecondaryColor
+			itemState |= StateSelected
 		}
-
-		if i == l.SelectedIndex || i == l.hoveredIndex {
-			img.PushSubImage(q2d.Rectangle{0, y, contentRect.Width(), lineHeight})
-			img.Fill(bg)
-			img.PopSubImage()
+		if i == l.hoveredIndex {
+			itemState |= StateHovered
 		}
+		st := theme.Style(StyleListItem, itemState)
+		DrawBox(img, q2d.Rectangle{0, y, contentRect.Width(), lineHeight}, st)
 
-		x := theme.Padding.Left
+		x := st.Padding.Left
 		if item.Icon != IconNone {
-			iconY := y + (lineHeight-IconSize)/2
-			DrawIcon(img, item.Icon, q2d.Point{x, iconY}, theme.TextColor)
-			x += IconSize + theme.Spacing
+			iconY := y + (lineHeight-theme.IconSize())/2
+			theme.DrawIcon(img, item.Icon, q2d.Point{x, iconY}, st.Text)
+			x += theme.IconSize() + theme.Spacing
 		}
 
 		textY := y + (lineHeight-textHeight)/2
-		img.Text(q2d.Point{x, textY}, theme.TextColor, theme.Font, false, "%s", item.Text)
+		img.Text(q2d.Point{x, textY}, st.Text, theme.Font, false, "%s", item.Text)
+	}
+	if l.dropIndex >= 0 {
+		// Insertion mark
+		y := min(l.dropIndex*lineHeight-l.ScrollOffset, contentRect.Height()-2)
+		DrawBox(img, q2d.Rectangle{0, max(y-1, 0), contentRect.Width(), 2}, theme.Style(StyleDivider, StateSelected))
 	}
 	img.PopSubImage() // Pop content clip
 
@@ -299,19 +486,14 @@ func (l *List) Draw(img *q2d.Image) {
 	if maxScroll > 0 {
 		trackH := viewportHeight
 		thumbH := int(float64(trackH) * float64(trackH) / float64(contentHeight))
-		if thumbH < 20 {
-			thumbH = 20
+		if thumbH < theme.Px(minThumbSize) {
+			thumbH = theme.Px(minThumbSize)
 		}
 		thumbY := int(float64(l.ScrollOffset) / float64(maxScroll) * float64(trackH-thumbH))
 
-		// Track
-		img.PushSubImage(q2d.Rectangle{l.Rect.Width() - barSize - 1, 1, barSize, trackH})
-		img.Fill(theme.BackgroundColor.Lighten(0.1)) // Lighter track
-		img.PopSubImage()
-
-		// Thumb
-		img.PushSubImage(q2d.Rectangle{l.Rect.Width() - barSize - 1, 1 + thumbY, barSize, thumbH})
-		img.Fill(theme.BorderColor)
-		img.PopSubImage()
+		drawScrollBar(img, theme,
+			q2d.Rectangle{l.Rect.Width() - barSize - 1, 1, barSize, trackH},
+			q2d.Rectangle{l.Rect.Width() - barSize - 1, 1 + thumbY, barSize, thumbH},
+			l.dragging)
 	}
 }
@@ -1,6 +1,9 @@
 package qui
 
 import (
+	"slices"
+	"time"
+
 	"github.com/qbradq/q2d"
 	"golang.org/x/image/font"
 )
@@ -15,19 +18,88 @@ type Master struct {
 	FocusedWidget Focusable
 	MousePos      q2d.Point
 
-	// Theme
+	// MenuBar receives key events not handled by open popups before the
+	// focused widget, enabling keyboard access to the menus.
+	MenuBar *MenuBar
+
+	// Shortcut registry, see BindShortcut
+	shortcuts     []*ShortcutBinding
+	pendingChords Shortcut
+	pendingTime   time.Time
+
+	// Widget the mouse button was last pressed on, for click events
+	pressTarget Widget
+	// Click counting and long presses, see countClicks
+	clicks         int
+	pressTime      time.Time
+	pressPos       q2d.Point
+	pressButton    int
+	longPressArmed bool
+	// Widget the pointer is captured to, see CapturePointer
+	capture         Widget
+	captureImplicit bool
+	// Drag and drop, see StartDrag
+	drag        *dragState
+	dragPending bool
+	// Touch points and gesture recognition, see touchEvent
+	touch touchState
+
+	// SpatialNavigation turns the arrow keys, Enter, Space and Escape into
+	// NavEvents when the focused widget does not use them, so that the UI can
+	// be driven like a game menu.
+	SpatialNavigation bool
+
+	// OnCaretRect is called when the text caret of the focused widget moves,
+	// so that the host can place the candidate window of an input method.
+	// ok is false when the focused widget does not edit text. See CaretRect.
+	OnCaretRect func(r q2d.Rectangle, ok bool)
+	caret       q2d.Rectangle
+	caretShown  bool
+
+	// Size of the screen as of the last Layout
+	size Size
+
+	// Theme is inherited by the root and the overlays. Use SetTheme to change
+	// it while the UI is shown.
 	Theme *Theme
+	fade  *themeFade // See SetTheme
+
+	// UI scale, see SetScale
+	scale      float64
+	scaled     *Theme // scaledFrom at the scale
+	scaledFrom *Theme
+	fonts      fontCache
 }
 
 func NewMaster(root Widget, theme *Theme) *Master {
-	return &Master{
+	m := &Master{
 		Root:     root,
 		Theme:    theme,
 		Overlays: make([]Widget, 0),
 	}
+	m.attach(root)
+	return m
+}
+
+// attach makes the widgets inherit the theme of m, see BaseWidget.GetTheme.
+func (m *Master) attach(widgets ...Widget) {
+	for _, w := range widgets {
+		if a, ok := w.(interface{ setMaster(*Master) }); ok {
+			a.setMaster(m)
+		}
+	}
+}
+
+// attachOverlay attaches w to om if om is a Master. Overlays call it before
+// measuring a widget they are about to push so that it has the right theme.
+func attachOverlay(om OverlayManager, w Widget) {
+	if m, ok := om.(*Master); ok {
+		m.attach(w)
+	}
 }
 
 func (m *Master) PushOverlay(w Widget) {
+	m.attach(w)
 	if mo, ok := w.(ManagedOverlay); ok {
 		mo.SetOverlayManager(m)
 	}
@@ -44,11 +116,46 @@ func (m *Master) PopOverlay() {
 	}
 }
 
-func (m *Master) Layout(size Size) {
-	if m.Theme != nil {
-		DefaultTheme = m.Theme
+func (m *Master) RemoveOverlay(w Widget) {
+	for i, overlay := range m.Overlays {
+		if overlay == w {
+			m.Overlays = append(m.Overlays[:i], m.Overlays[i+1:]...)
+			if d, ok := overlay.(Dismissable); ok {
+				d.OnDismiss()
+			}
+			return
+		}
+	}
+}
+
+func (m *Master) ScreenSize() Size {
+	return m.size
+}
+
+// OpenPopup shows menu at pos, flipping it to the other side of pos where it
+// would otherwise extend past the edge of the screen. Open popup menus are
+// closed first.
+func (m *Master) OpenPopup(menu *PopupMenu, pos q2d.Point) {
+	for len(m.Overlays) > 0 {
+		if _, ok := m.Overlays[len(m.Overlays)-1].(*PopupMenu); !ok {
+			break
+		}
+		m.PopOverlay()
 	}
+
+	// Open below the cursor so the release of the opening click lands outside
+	// the menu
+	m.attach(menu)
+	sz := menu.MinSize()
+	menu.SetRect(placePopup(sz, q2d.Rectangle{pos.X(), pos.Y(), 1, 1}, m.size, false))
+	menu.Layout(sz)
+	m.PushOverlay(menu)
+}
+
+func (m *Master) Layout(size Size) {
+	m.size = size
 	if m.Root != nil {
+		m.attach(m.Root)
 		m.Root.SetRect(q2d.Rectangle{0, 0, size.Width, size.Height})
 		m.Root.Layout(size)
 	}
@@ -59,120 +166,185 @@ func (m *Master) Layout(size Size) {
 	}
 }
 
+// Event dispatches e to the handlers subscribed on the path from the root to
+// the target widget, see EventContext, then performs the default handling of
+// the event unless a handler prevented it.
 func (m *Master) Event(e Event) bool {
-	// Update MousePos
 	if mouse, ok := e.(MouseEvent); ok {
 		m.MousePos = mouse.Pos
+		e = m.countClicks(mouse)
+	}
+	if m.drag != nil && m.dragEvent(e) {
+		return true
+	}
+	if touch, ok := e.(TouchEvent); ok {
+		return m.touchEvent(touch)
+	}
 
-		if mouse.TypeVal == EventMouseDown {
-			// Handle Focus
-			var target Widget
-			// Check overlays
-			for i := len(m.Overlays) - 1; i >= 0; i-- {
-				if w := m.Overlays[i].FindWidgetAt(mouse.Pos); w != nil {
-					target = w
-					break
-				}
-			}
-			// Check root
-			if target == nil && m.Root != nil {
-				target = m.Root.FindWidgetAt(mouse.Pos)
-			}
+	// Disabled widgets get no events
+	target := m.eventTarget(e)
+	if !interactive(target) {
+		target = nil
+	}
+	handled := m.dispatch(target, e)
+	if !handled {
+		if mouse, ok := e.(MouseEvent); ok && m.capture != nil && mouse.TypeVal != EventMouseDown {
+			handled = m.capture.Event(e)
+		} else {
+			handled = m.defaultEvent(target, e)
+		}
+	}
 
-			if target != nil {
-				if f, ok := target.(Focusable); ok {
-					if m.FocusedWidget != f {
-						if m.FocusedWidget != nil {
-							m.FocusedWidget.Unfocus()
-						}
-						f.Focus()
-						m.FocusedWidget = f
-					}
-				} else {
-					// Clicked non-focusable
-					if m.FocusedWidget != nil {
-						m.FocusedWidget.Unfocus()
-						m.FocusedWidget = nil
-					}
-				}
-			} else {
-				// Clicked nothing
-				if m.FocusedWidget != nil {
-					m.FocusedWidget.Unfocus()
-					m.FocusedWidget = nil
-				}
+	mouse, ok := e.(MouseEvent)
+	if !ok {
+		return handled
+	}
+	switch mouse.TypeVal {
+	case EventMouseDown:
+		m.pressTarget = m.widgetAt(mouse.Pos)
+		m.dragPending = true
+		if m.capture == nil {
+			m.grabPointer(m.pressTarget)
+		}
+	case EventMouseUp:
+		// Synthesize clicks
+		if t := m.widgetAt(mouse.Pos); t != nil && t == m.pressTarget && interactive(t) {
+			mouse.TypeVal = EventClick
+			if m.dispatch(t, mouse) {
+				handled = true
 			}
 		}
+		m.pressTarget = nil
+		m.dragPending = false
+		m.ReleasePointer()
+	case EventMouseMove:
+		m.UpdateHover(mouse.Pos)
+		m.checkLongPress()
+		m.checkDrag(mouse.Pos)
+	}
+	if m.captureImplicit {
+		if g, ok := m.capture.(PointerGrabber); !ok || !g.GrabsPointer() {
+			m.ReleasePointer()
+		}
 	}
+	return handled
+}
+
+// defaultEvent performs the built-in handling of e. Mouse, scroll and gesture
+// events are offered to target, the widget under the pointer or the one the
+// pointer is captured to, and then its ancestors until one of them consumes
+// it. Keys go the same way from the focused widget, or the root if nothing
+// has the focus, after open popups, the menu bar and shortcuts have seen
+// them.
+func (m *Master) defaultEvent(target Widget, e Event) bool {
+	switch evt := e.(type) {
+	case MouseEvent:
+		if evt.TypeVal != EventMouseDown {
+			return m.deliver(target, e)
+		}
+		// Clicking ends keyboard navigation of the menu bar
+		if m.MenuBar != nil {
+			m.MenuBar.active = false
+		}
 
-	// Handle Keyboard/Text events via FocusedWidget
-	switch e.(type) {
-	case KeyEvent, TextInputEvent:
-		if m.FocusedWidget != nil {
-			// We need to cast Focusable back to Widget to call Event?
-			// Focusable interface doesn't have Event().
-			// But all widgets have Event().
-			// We can assume FocusedWidget is a Widget.
-			if w, ok := m.FocusedWidget.(Widget); ok {
-				if w.Event(e) {
-					return true
+		// Context menus
+		if target != nil && evt.Button == MouseButtonRight {
+			// The innermost widget with a menu wins
+			for w := target; w != nil; w = parentOf(w) {
+				if p, ok := w.(ContextMenuProvider); ok {
+					if menu := p.ContextMenuAt(evt.Pos); menu != nil {
+						m.OpenPopup(menu, evt.Pos)
+						return true
+					}
 				}
 			}
 		}
-	case ScrollEvent:
-		// Route to widget under mouse
-		var target Widget
-		// Check overlays
+
+		// Clicking something that can't be focused, or nothing, clears the
+		// focus
+		f, _ := target.(Focusable)
+		m.SetFocus(f)
+
+		// A click that closes overlays does nothing else
+		if m.dismissOverlays(evt.Pos) {
+			return true
+		}
+		return m.deliver(target, e)
+	case KeyEvent, TextInputEvent, CompositionEvent:
+		focus, _ := m.FocusedWidget.(Widget)
+		if focus != nil && !interactive(focus) {
+			// Disabled or hidden since it was focused
+			m.SetFocus(nil)
+			focus = nil
+		}
+		// Open popups over the focused widget and the menu bar see keys first
 		for i := len(m.Overlays) - 1; i >= 0; i-- {
-			if w := m.Overlays[i].FindWidgetAt(m.MousePos); w != nil {
-				target = w
+			if focus != nil && IsDescendant(focus, m.Overlays[i]) {
 				break
 			}
+			if m.Overlays[i].Event(e) {
+				return true
+			}
 		}
-		// Check root
-		if target == nil && m.Root != nil {
-			target = m.Root.FindWidgetAt(m.MousePos)
-		}
-
-		if target != nil {
-			if target.Event(e) {
+		if key, ok := e.(KeyEvent); ok && m.MenuBar != nil {
+			if m.MenuBar.HandleKey(key) {
 				return true
 			}
 		}
-	}
-
-	// 1. Handle Overlays (Top to Bottom)
-	for i := len(m.Overlays) - 1; i >= 0; i-- {
-		overlay := m.Overlays[i]
-		if overlay.Event(e) {
+		if key, ok := e.(KeyEvent); ok && m.handleShortcut(key) {
+			return true
+		}
+		target = focus
+		if target == nil {
+			target = m.Root
+		}
+		if m.deliver(target, e) {
 			return true
 		}
-		// If click outside top overlay, should we close it?
-		if mouse, ok := e.(MouseEvent); ok && mouse.TypeVal == EventMouseDown {
-			if !overlay.GetRect().Contains(mouse.Pos) {
-				m.PopOverlay()
+		if key, ok := e.(KeyEvent); ok && m.SpatialNavigation && key.TypeVal == EventKeyDown && key.Mods == 0 {
+			if a, ok := navKeys[key.Key]; ok && m.Navigate(a) {
 				return true
 			}
 		}
-	}
-
-	// 2. Handle Root
-	if m.Root != nil {
-		if m.Root.Event(e) {
+		return false
+	case ScrollEvent, GestureEvent:
+		return m.deliver(target, e)
+	case NavEvent:
+		if w, ok := m.FocusedWidget.(Widget); ok && interactive(w) && w.Event(e) {
 			return true
 		}
+		return m.Navigate(evt.Action)
 	}
-
 	return false
 }
 
-func (m *Master) Draw(img *q2d.Image) {
-	if m.Theme != nil {
-		DefaultTheme = m.Theme // Set global default theme for convenience
+// dismissOverlays closes the overlays above the one clicked at pos, except
+// modeless ones. It returns true if any were closed.
+func (m *Master) dismissOverlays(pos q2d.Point) bool {
+	dismissed := false
+	for i := len(m.Overlays) - 1; i >= 0; i-- {
+		overlay := m.Overlays[i]
+		if overlay.GetRect().Contains(pos) {
+			break
+		}
+		if ml, ok := overlay.(Modeless); ok && ml.IsModeless() {
+			continue
+		}
+		m.RemoveOverlay(overlay)
+		dismissed = true
 	}
+	return dismissed
+}
+
+func (m *Master) Draw(img *q2d.Image) {
+	m.checkLongPress()
+	m.updateCaret()
+	m.stepFade()
+	m.stepAnimations()
 
 	// Draw Root
 	if m.Root != nil {
+		m.attach(m.Root)
 		m.Root.Draw(img)
 	}
 
@@ -181,6 +353,11 @@ func (m *Master) Draw(img *q2d.Image) {
 		overlay.Draw(img)
 	}
 
+	if m.drag != nil {
+		m.drawDrag(img)
+		return
+	}
+
 	// Draw Tooltip
 	m.UpdateHover(m.MousePos)
 	if m.HoveredWidget != nil {
@@ -191,37 +368,55 @@ func (m *Master) Draw(img *q2d.Image) {
 	}
 }
 
+// UpdateHover finds the widget under p and sends EventMouseLeave and
+// EventMouseEnter to the widgets the pointer moved off and onto. The hovered
+// widget does not change while the pointer is captured.
 func (m *Master) UpdateHover(p q2d.Point) {
-	m.HoveredWidget = nil
+	if m.capture != nil {
+		return
+	}
+	old := m.HoveredWidget
+	m.HoveredWidget = m.widgetAt(p)
+	if m.HoveredWidget == old {
+		return
+	}
 
-	// Check overlays first (top to bottom)
-	for i := len(m.Overlays) - 1; i >= 0; i-- {
-		overlay := m.Overlays[i]
-		if found := overlay.FindWidgetAt(p); found != nil {
-			m.HoveredWidget = found
-			return
+	// Target first, root last
+	var oldPath, newPath []Widget
+	if old != nil {
+		oldPath = append([]Widget{old}, Ancestors(old)...)
+	}
+	if m.HoveredWidget != nil {
+		newPath = append([]Widget{m.HoveredWidget}, Ancestors(m.HoveredWidget)...)
+	}
+	for _, w := range oldPath {
+		if !slices.Contains(newPath, w) {
+			m.notify(w, MouseEvent{TypeVal: EventMouseLeave, Pos: p})
 		}
 	}
-
-	// Check Root
-	if m.Root != nil {
-		m.HoveredWidget = m.Root.FindWidgetAt(p)
+	for i := len(newPath) - 1; i >= 0; i-- {
+		if !slices.Contains(oldPath, newPath[i]) {
+			m.notify(newPath[i], MouseEvent{TypeVal: EventMouseEnter, Pos: p})
+		}
 	}
 }
 
 func (m *Master) drawTooltip(img *q2d.Image, text string) {
-	if m.Theme == nil || m.Theme.Font == nil {
+	theme := m.currentTheme()
+	if theme == nil || theme.Font == nil {
 		return
 	}
 
+	st := theme.Style(StyleTooltip, 0)
+
 	// Calculate size
-	width := font.MeasureString(m.Theme.Font, text).Ceil() + m.Theme.Padding.Left + m.Theme.Padding.Right
-	metrics := m.Theme.Font.Metrics()
-	height := (metrics.Ascent + metrics.Descent).Ceil() + m.Theme.Padding.Top + m.Theme.Padding.Bottom
+	width := font.MeasureString(theme.Font, text).Ceil() + st.Padding.Left + st.Padding.Right
+	metrics := theme.Font.Metrics()
+	height := (metrics.Ascent + metrics.Descent).Ceil() + st.Padding.Top + st.Padding.Bottom
 
 	// Position: near mouse
-	x := m.MousePos.X() + 10
-	y := m.MousePos.Y() + 10
+	x := m.MousePos.X() + theme.Px(10)
+	y := m.MousePos.Y() + theme.Px(10)
 
 	// Clamp to screen
 	if x+width > img.Rect.Width() {
@@ -233,11 +428,11 @@ func (m *Master) drawTooltip(img *q2d.Image, text string) {
 
 	rect := q2d.Rectangle{x, y, width, height}
 
+	DrawShadow(img, rect, st.Radius, theme.Shadow)
 	img.PushSubImage(rect)
-	img.Fill(m.Theme.BackgroundColor)
-	img.Border(m.Theme.BorderColor)
+	DrawBox(img, q2d.Rectangle{0, 0, width, height}, st)
 
 	textY := (height - (metrics.Ascent + metrics.Descent).Ceil()) / 2
-	img.Text(q2d.Point{m.Theme.Padding.Left, textY}, m.Theme.TextColor, m.Theme.Font, false, "%s", text)
+	img.Text(q2d.Point{st.Padding.Left, textY}, st.Text, theme.Font, false, "%s", text)
 	img.PopSubImage()
 }
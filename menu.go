@@ -1,18 +1,56 @@
 package qui
 
 import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/qbradq/q2d"
 	"golang.org/x/image/font"
 )
 
+// SubmenuDelay is how long the pointer has to rest on a menu item before its
+// submenu opens.
+var SubmenuDelay = 300 * time.Millisecond
+
+type MenuItemKind int
+
+const (
+	MenuItemNormal MenuItemKind = iota
+	// MenuItemCheck items toggle Checked when activated.
+	MenuItemCheck
+	// MenuItemRadio items check themselves and uncheck the items of the same
+	// RadioGroup in their menu when activated.
+	MenuItemRadio
+	// MenuItemSeparator items draw a horizontal rule and cannot be activated.
+	MenuItemSeparator
+)
+
+// MenuItem is an entry of a PopupMenu or MenuBar. An & in Text marks the next
+// letter as the item's mnemonic, which is underlined and activates the item
+// from the keyboard. Use && for a literal &.
 type MenuItem struct {
 	BaseWidget
 	Text   string
 	Icon   Icon
 	Action func()
 
-	hovered bool
-	parent  *PopupMenu
+	Kind       MenuItemKind
+	Checked    bool
+	RadioGroup string
+	// OnChange is called with the new state when a check or radio item is
+	// activated.
+	OnChange func(checked bool)
+	// Shortcut is shown right-aligned, e.g. "Ctrl+S".
+	Shortcut string
+	// Submenu is opened beside the item when it is hovered or activated.
+	Submenu *PopupMenu
+
+	hovered     bool
+	highlighted bool // Keyboard highlight
+	menu        *PopupMenu
+	action      *Action
 }
 
 func NewMenuItem(text string, icon Icon, action func()) *MenuItem {
@@ -23,39 +61,183 @@ func NewMenuItem(text string, icon Icon, action func()) *MenuItem {
 	}
 }
 
+func NewSeparator() *MenuItem {
+	return &MenuItem{Kind: MenuItemSeparator}
+}
+
+func NewSubmenuItem(text string, icon Icon, submenu *PopupMenu) *MenuItem {
+	return &MenuItem{
+		Text:    text,
+		Icon:    icon,
+		Submenu: submenu,
+	}
+}
+
+func NewCheckMenuItem(text string, checked bool, onChange func(bool)) *MenuItem {
+	return &MenuItem{
+		Text:     text,
+		Kind:     MenuItemCheck,
+		Checked:  checked,
+		OnChange: onChange,
+	}
+}
+
+func NewRadioMenuItem(text, group string, checked bool, onChange func(bool)) *MenuItem {
+	return &MenuItem{
+		Text:       text,
+		Kind:       MenuItemRadio,
+		RadioGroup: group,
+		Checked:    checked,
+		OnChange:   onChange,
+	}
+}
+
+// NewActionMenuItem creates a menu item bound to a, see BindAction.
+func NewActionMenuItem(a *Action) *MenuItem {
+	m := &MenuItem{}
+	m.BindAction(a)
+	return m
+}
+
+// BindAction makes the item show and trigger a. Checkable actions are shown
+// as check items. Activating the item calls a.Trigger instead of Action and
+// OnChange. Nil unbinds the item.
+func (m *MenuItem) BindAction(a *Action) {
+	if m.action != nil {
+		m.action.unbind(m)
+	}
+	m.action = a
+	if a != nil {
+		a.bind(m)
+	}
+}
+
+// sync copies the state of the bound action.
+func (m *MenuItem) sync() {
+	a := m.action
+	if a == nil {
+		return
+	}
+	m.Text = a.Text
+	m.Icon = a.Icon
+	m.Tooltip = a.Tooltip
+	m.Shortcut = a.Shortcut
+	m.Disabled = a.Disabled
+	m.Checked = a.Checked
+	m.Kind = MenuItemNormal
+	if a.Checkable {
+		m.Kind = MenuItemCheck
+	}
+}
+
+func (m *MenuItem) GetTooltip() string {
+	return m.Tooltip
+}
+
 func (m *MenuItem) MinSize() Size {
 	theme := m.GetTheme()
 	if theme == nil || theme.Font == nil {
 		return Size{0, 0}
 	}
-	width := font.MeasureString(theme.Font, m.Text).Ceil()
-	width += IconSize + theme.Spacing + theme.Padding.Left + theme.Padding.Right
+	if m.Kind == MenuItemSeparator {
+		return Size{0, theme.Spacing*2 + 1}
+	}
+	pad := theme.Style(StyleMenuItem, 0).Padding
+	text, _, _ := parseMnemonic(m.Text)
+	width := font.MeasureString(theme.Font, text).Ceil()
+	width += theme.IconSize() + theme.Spacing + pad.Left + pad.Right
+	if m.Shortcut != "" {
+		width += theme.Spacing*4 + font.MeasureString(theme.Font, m.Shortcut).Ceil()
+	}
+	if m.Submenu != nil {
+		width += theme.Spacing + theme.IconSize()
+	}
 
 	metrics := theme.Font.Metrics()
 	height := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > height {
-		height = IconSize
+	if theme.IconSize() > height {
+		height = theme.IconSize()
 	}
-	height += theme.Padding.Top + theme.Padding.Bottom
+	height += pad.Top + pad.Bottom
 
 	return Size{width, height}
 }
 
+// Mnemonic returns the upper case mnemonic letter of the item, or 0.
+func (m *MenuItem) Mnemonic() rune {
+	_, r, _ := parseMnemonic(m.Text)
+	return r
+}
+
+// Activate performs the item's action as if it had been clicked.
+func (m *MenuItem) Activate() {
+	if m.Disabled || m.Kind == MenuItemSeparator {
+		return
+	}
+	if m.Submenu != nil {
+		if m.menu != nil {
+			m.menu.openSubmenu(m)
+		}
+		return
+	}
+
+	switch {
+	case m.action != nil:
+		m.action.Trigger()
+	case m.Kind == MenuItemCheck:
+		m.Checked = !m.Checked
+		if m.OnChange != nil {
+			m.OnChange(m.Checked)
+		}
+	case m.Kind == MenuItemRadio:
+		if m.menu != nil {
+			for _, item := range m.menu.Items {
+				if item != m && item.Kind == MenuItemRadio && item.RadioGroup == m.RadioGroup && item.Checked {
+					item.Checked = false
+					if item.OnChange != nil {
+						item.OnChange(false)
+					}
+				}
+			}
+		}
+		if !m.Checked {
+			m.Checked = true
+			if m.OnChange != nil {
+				m.OnChange(true)
+			}
+		}
+	}
+
+	if m.Action != nil && m.action == nil {
+		m.Action()
+	}
+	if m.menu != nil {
+		root := m.menu
+		for root.parentMenu != nil {
+			root = root.parentMenu
+		}
+		if root.onActivate != nil {
+			root.onActivate()
+		}
+		m.menu.Close()
+	}
+}
+
 func (m *MenuItem) Event(e Event) bool {
 	switch evt := e.(type) {
 	case MouseEvent:
+		if evt.TypeVal == EventMouseLeave {
+			m.hovered = false
+			return false
+		}
 		if m.Rect.Contains(evt.Pos) {
 			if evt.TypeVal == EventMouseMove {
-				m.hovered = true
-				return true
+				// Moves bubble on to the menu, which opens submenus
+				m.hovered = m.Kind != MenuItemSeparator
+				return false
 			}
 			if evt.TypeVal == EventMouseUp {
-				if m.Action != nil {
-					m.Action()
-				}
-				if m.parent != nil {
-					m.parent.Close()
-				}
+				m.Activate()
 				return true
 			}
 		} else {
@@ -65,6 +247,13 @@ func (m *MenuItem) Event(e Event) bool {
 	return false
 }
 
+func (m *MenuItem) FindWidgetAt(pos q2d.Point) Widget {
+	if m.Rect.Contains(pos) {
+		return m
+	}
+	return nil
+}
+
 func (m *MenuItem) Draw(img *q2d.Image) {
 	theme := m.GetTheme()
 	if theme == nil {
@@ -74,31 +263,67 @@ func (m *MenuItem) Draw(img *q2d.Image) {
 	img.PushSubImage(m.Rect)
 	defer img.PopSubImage()
 
-	if m.hovered {
-		img.Fill(theme.PrimaryColor)
+	if m.Kind == MenuItemSeparator {
+		img.HLine(m.Rect.Height()/2, theme.Padding.Left, m.Rect.Width()-theme.Padding.Right, 1, theme.BorderColor)
+		return
 	}
 
+	// Items stay highlighted while their submenu is open
+	state := m.state()
+	open := m.Submenu != nil && m.menu != nil && m.menu.openSub == m.Submenu
+	if (m.hovered && !m.Disabled) || m.highlighted || open {
+		state |= StateHovered
+	}
+	if m.Checked {
+		state |= StateSelected
+	}
+	st := theme.Style(StyleMenuItem, state)
+	DrawBox(img, q2d.Rectangle{0, 0, m.Rect.Width(), m.Rect.Height()}, st)
+
+	textColor := st.Text
+
 	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	contentHeight := textHeight
-	if IconSize > contentHeight {
-		contentHeight = IconSize
+	if theme.IconSize() > contentHeight {
+		contentHeight = theme.IconSize()
 	}
 
 	y := (m.Rect.Height() - contentHeight) / 2
 	if y < 0 {
 		y = 0
 	}
+	iconY := y + (contentHeight-theme.IconSize())/2
+	textY := y + (contentHeight-textHeight)/2
+
+	icon := m.Icon
+	if m.Kind == MenuItemCheck && m.Checked {
+		icon = IconCheck
+	} else if m.Kind == MenuItemRadio && m.Checked {
+		icon = IconRadioOn
+	}
 
-	x := theme.Padding.Left
-	if m.Icon != IconNone {
-		iconY := y + (contentHeight-IconSize)/2
-		DrawIcon(img, m.Icon, q2d.Point{x, iconY}, theme.TextColor)
-		x += IconSize + theme.Spacing
+	x := st.Padding.Left
+	if icon != IconNone {
+		theme.DrawIcon(img, icon, q2d.Point{x, iconY}, textColor)
+		x += theme.IconSize() + theme.Spacing
+	} else if m.menu != nil {
+		// Keep the text of all items in a popup aligned
+		x += theme.IconSize() + theme.Spacing
 	}
 
-	textY := y + (contentHeight-textHeight)/2
-	img.Text(q2d.Point{x, textY}, theme.TextColor, theme.Font, false, "%s", m.Text)
+	drawMnemonicText(img, q2d.Point{x, textY}, textColor, theme, m.Text)
+
+	right := m.Rect.Width() - st.Padding.Right
+	if m.Submenu != nil {
+		right -= theme.IconSize()
+		theme.DrawIcon(img, IconArrowRight, q2d.Point{right, iconY}, textColor)
+		right -= theme.Spacing
+	}
+	if m.Shortcut != "" {
+		w := font.MeasureString(theme.Font, m.Shortcut).Ceil()
+		img.Text(q2d.Point{right - w, textY}, textColor, theme.Font, false, "%s", m.Shortcut)
+	}
 }
 
 type PopupMenu struct {
@@ -107,14 +332,36 @@ type PopupMenu struct {
 	OnDismissFunc func()
 
 	overlayManager OverlayManager
+
+	hoverItem  *MenuItem
+	hoverStart time.Time
+	keyboard   bool // The highlight was last moved with the keyboard
+	openSub    *PopupMenu
+	parentMenu *PopupMenu
+	onActivate func()
 }
 
 func NewPopupMenu(items ...*MenuItem) *PopupMenu {
-	p := &PopupMenu{Items: items}
+	p := &PopupMenu{}
+	p.Add(items...)
+	return p
+}
+
+// Add appends items to the menu.
+func (p *PopupMenu) Add(items ...*MenuItem) {
 	for _, item := range items {
-		item.parent = p
+		item.menu = p
+		item.SetParent(p)
 	}
-	return p
+	p.Items = append(p.Items, items...)
+}
+
+func (p *PopupMenu) GetChildren() []Widget {
+	ret := make([]Widget, len(p.Items))
+	for i, item := range p.Items {
+		ret[i] = item
+	}
+	return ret
 }
 
 func (p *PopupMenu) MinSize() Size {
@@ -142,10 +389,15 @@ func (p *PopupMenu) Layout(available Size) Size {
 }
 
 func (p *PopupMenu) Draw(img *q2d.Image) {
+	p.updateSubmenu(time.Now())
+
 	// Draw background
 	theme := p.GetTheme()
+	st := theme.Style(StyleMenu, p.state())
+	box := q2d.Rectangle{0, 0, p.Rect.Width(), p.Rect.Height()}
+	DrawShadow(img, p.Rect, st.Radius, theme.Shadow)
 	img.PushSubImage(p.Rect)
-	img.Fill(theme.BackgroundColor.Darken(0.1))
+	DrawBox(img, box, Style{Background: st.Background, Gradient: st.Gradient, Radius: st.Radius})
 	img.PopSubImage()
 
 	for _, item := range p.Items {
@@ -154,23 +406,174 @@ func (p *PopupMenu) Draw(img *q2d.Image) {
 
 	// Draw border on top
 	img.PushSubImage(p.Rect)
-	img.Border(theme.BorderColor)
+	DrawBox(img, box, Style{Border: st.Border, BorderWidth: st.BorderWidth, Radius: st.Radius})
 	img.PopSubImage()
 }
 
-func (p *PopupMenu) Event(e Event) bool {
-	handled := false
-	for _, item := range p.Items {
-		if item.Event(e) {
-			handled = true
-			if e.Type() != EventMouseMove {
+// updateSubmenu opens the submenu of the hovered item, or closes the open one,
+// once the pointer has rested for SubmenuDelay.
+func (p *PopupMenu) updateSubmenu(now time.Time) {
+	if p.keyboard || p.hoverItem == nil || now.Sub(p.hoverStart) < SubmenuDelay {
+		return
+	}
+	if p.hoverItem.Submenu != nil {
+		if !p.hoverItem.Disabled {
+			p.openSubmenu(p.hoverItem)
+		}
+	} else {
+		p.closeSubmenu()
+	}
+}
+
+func (p *PopupMenu) openSubmenu(item *MenuItem) {
+	sub := item.Submenu
+	if p.openSub == sub {
+		return
+	}
+	p.closeSubmenu()
+	if p.overlayManager == nil {
+		return
+	}
+
+	attachOverlay(p.overlayManager, sub)
+	sz := sub.MinSize()
+	sub.SetRect(placePopup(sz, item.Rect, screenSize(p.overlayManager), true))
+	sub.Layout(sz)
+	sub.parentMenu = p
+	p.openSub = sub
+	p.overlayManager.PushOverlay(sub)
+}
+
+func (p *PopupMenu) closeSubmenu() {
+	if p.openSub != nil {
+		sub := p.openSub
+		p.openSub = nil
+		sub.closeSubmenu()
+		sub.parentMenu = nil
+		if p.overlayManager != nil {
+			removeOverlay(p.overlayManager, sub)
+		}
+	}
+}
+
+// highlight moves the keyboard highlight to item, which may be nil.
+func (p *PopupMenu) highlight(item *MenuItem) {
+	for _, it := range p.Items {
+		it.highlighted = it == item
+		it.hovered = false
+	}
+	p.hoverItem = item
+	p.keyboard = true
+}
+
+// highlightedIndex returns the index of the highlighted or hovered item, or -1.
+func (p *PopupMenu) highlightedIndex() int {
+	for i, item := range p.Items {
+		if item.highlighted || item.hovered {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveHighlight moves the keyboard highlight dir items up or down, wrapping
+// around and skipping separators.
+func (p *PopupMenu) moveHighlight(dir int) {
+	n := len(p.Items)
+	i := p.highlightedIndex()
+	if i < 0 && dir < 0 {
+		i = 0
+	}
+	for range p.Items {
+		i = (i + dir + n) % n
+		if p.Items[i].Kind != MenuItemSeparator {
+			p.highlight(p.Items[i])
+			return
+		}
+	}
+}
+
+// activate activates item from the keyboard, highlighting the first entry of
+// the submenu it opens if any.
+func (p *PopupMenu) activate(item *MenuItem) {
+	item.Activate()
+	if item.Submenu != nil && p.openSub == item.Submenu {
+		item.Submenu.highlight(nil)
+		item.Submenu.moveHighlight(1)
+	}
+}
+
+// handleKey implements keyboard navigation. Only the innermost open menu
+// handles keys, Left and Right keys it does not use fall through to the menus
+// below it and the MenuBar.
+func (p *PopupMenu) handleKey(e KeyEvent) bool {
+	if e.TypeVal != EventKeyDown || p.openSub != nil {
+		return false
+	}
+	var item *MenuItem
+	if i := p.highlightedIndex(); i >= 0 {
+		item = p.Items[i]
+	}
+
+	switch e.Key {
+	case KeyUp:
+		p.moveHighlight(-1)
+	case KeyDown:
+		p.moveHighlight(1)
+	case KeyEnter, KeySpace:
+		if item != nil {
+			p.activate(item)
+		}
+	case KeyRight:
+		if item == nil || item.Submenu == nil || item.Disabled {
+			return false
+		}
+		p.activate(item)
+	case KeyLeft:
+		if p.parentMenu == nil {
+			return false
+		}
+		p.parentMenu.closeSubmenu()
+	case KeyEscape:
+		if p.parentMenu != nil {
+			p.parentMenu.closeSubmenu()
+		} else {
+			p.Close()
+		}
+	default:
+		key := mnemonicKey(e.Key)
+		for _, it := range p.Items {
+			if it.Kind != MenuItemSeparator && it.Mnemonic() == key && key != 0 {
+				p.highlight(it)
+				p.activate(it)
 				return true
 			}
 		}
+		return false
 	}
-	if handled {
-		return true
+	return true
+}
+
+func (p *PopupMenu) Event(e Event) bool {
+	if key, ok := e.(KeyEvent); ok {
+		return p.handleKey(key)
+	}
+
+	if mouse, ok := e.(MouseEvent); ok && mouse.TypeVal == EventMouseMove && p.Rect.Contains(mouse.Pos) {
+		if p.keyboard {
+			p.keyboard = false
+			for _, item := range p.Items {
+				item.highlighted = false
+			}
+		}
+		for _, item := range p.Items {
+			if item.Rect.Contains(mouse.Pos) && item != p.hoverItem {
+				p.hoverItem = item
+				p.hoverStart = time.Now()
+			}
+		}
 	}
+
 	if mouse, ok := e.(MouseEvent); ok {
 		if p.Rect.Contains(mouse.Pos) {
 			return true
@@ -180,6 +583,15 @@ func (p *PopupMenu) Event(e Event) bool {
 }
 
 func (p *PopupMenu) OnDismiss() {
+	p.hoverItem = nil
+	p.keyboard = false
+	for _, item := range p.Items {
+		item.hovered = false
+		item.highlighted = false
+	}
+	if p.parentMenu != nil && p.parentMenu.openSub == p {
+		p.parentMenu.openSub = nil
+	}
 	if p.OnDismissFunc != nil {
 		p.OnDismissFunc()
 	}
@@ -189,9 +601,17 @@ func (p *PopupMenu) SetOverlayManager(m OverlayManager) {
 	p.overlayManager = m
 }
 
+// Close closes the menu along with its open submenus and the menus it is a
+// submenu of.
 func (p *PopupMenu) Close() {
+	p.closeSubmenu()
+	parent := p.parentMenu
+	p.parentMenu = nil
 	if p.overlayManager != nil {
-		p.overlayManager.PopOverlay()
+		removeOverlay(p.overlayManager, p)
+	}
+	if parent != nil {
+		parent.Close()
 	}
 }
 
@@ -207,6 +627,94 @@ func (p *PopupMenu) FindWidgetAt(pos q2d.Point) Widget {
 	return p
 }
 
+// parseMnemonic strips the mnemonic markers from text. It returns the text to
+// display, the upper case mnemonic letter or 0 if there is none, and the byte
+// offset of the mnemonic in the display text.
+func parseMnemonic(text string) (string, rune, int) {
+	if !strings.ContainsRune(text, '&') {
+		return text, 0, -1
+	}
+	var b strings.Builder
+	var mnemonic rune
+	index := -1
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '&' && i+1 < len(runes) {
+			i++
+			r = runes[i]
+			if r != '&' && mnemonic == 0 {
+				mnemonic = unicode.ToUpper(r)
+				index = b.Len()
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), mnemonic, index
+}
+
+// mnemonicKey returns the mnemonic letter or digit typed by the key code key,
+// or 0 if key is not a letter or digit key.
+func mnemonicKey(key int) rune {
+	if key >= 'A' && key <= 'Z' || key >= '0' && key <= '9' {
+		return rune(key)
+	}
+	return 0
+}
+
+// drawMnemonicText draws text with its mnemonic letter underlined.
+func drawMnemonicText(img *q2d.Image, p q2d.Point, c q2d.Color, theme *Theme, text string) {
+	display, mnemonic, index := parseMnemonic(text)
+	img.Text(p, c, theme.Font, false, "%s", display)
+	if mnemonic == 0 {
+		return
+	}
+	_, size := utf8.DecodeRuneInString(display[index:])
+	x := p.X() + font.MeasureString(theme.Font, display[:index]).Ceil()
+	w := font.MeasureString(theme.Font, display[index:index+size]).Ceil()
+	y := p.Y() + theme.Font.Metrics().Ascent.Ceil() + 1
+	img.HLine(y, x, x+w, 1, c)
+}
+
+// placePopup returns the rectangle for a popup of size sz opened from anchor.
+// Popups open below anchor, or beside it for submenus, and flip to the other
+// side where they would extend past the edge of the screen.
+func placePopup(sz Size, anchor q2d.Rectangle, screen Size, beside bool) q2d.Rectangle {
+	var x, y int
+	if beside {
+		x = anchor.X() + anchor.Width()
+		y = anchor.Y()
+		if screen.Width > 0 && x+sz.Width > screen.Width {
+			x = anchor.X() - sz.Width
+		}
+		if screen.Height > 0 && y+sz.Height > screen.Height {
+			y = anchor.Y() + anchor.Height() - sz.Height
+		}
+	} else {
+		x = anchor.X()
+		y = anchor.Y() + anchor.Height()
+		if screen.Width > 0 && x+sz.Width > screen.Width {
+			x = anchor.X() + anchor.Width() - sz.Width
+		}
+		if screen.Height > 0 && y+sz.Height > screen.Height {
+			y = anchor.Y() - sz.Height
+		}
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	return q2d.Rectangle{x, y, sz.Width, sz.Height}
+}
+
+// MenuBar is a horizontal row of menus. When set as Master.MenuBar it can be
+// driven from the keyboard: pressing and releasing Alt on its own toggles
+// keyboard navigation of the menu headers, Alt plus a mnemonic letter opens
+// that menu, Left and Right move
+// between menus, Enter or Down opens the highlighted menu and Escape closes
+// one level.
 type MenuBar struct {
 	BaseWidget
 	Menus []*MenuItem // Using MenuItem as top level menu headers
@@ -216,6 +724,10 @@ type MenuBar struct {
 	Popups []*PopupMenu
 
 	OverlayManager OverlayManager
+
+	active    bool // Keyboard navigation of the headers
+	highlight int
+	altDown   bool // Alt is down and no other key was pressed since
 }
 
 func NewMenuBar() *MenuBar {
@@ -226,8 +738,17 @@ func NewMenuBar() *MenuBar {
 	}
 }
 
+func (m *MenuBar) GetChildren() []Widget {
+	ret := make([]Widget, len(m.Menus))
+	for i, item := range m.Menus {
+		ret[i] = item
+	}
+	return ret
+}
+
 func (m *MenuBar) AddMenu(title string, popup *PopupMenu) {
 	item := NewMenuItem(title, IconNone, nil)
+	item.SetParent(m)
 	m.Menus = append(m.Menus, item)
 	m.Popups = append(m.Popups, popup)
 
@@ -235,49 +756,151 @@ func (m *MenuBar) AddMenu(title string, popup *PopupMenu) {
 	index := len(m.Menus) - 1
 	item.Action = func() {
 		if m.OpenMenuIndex == index {
-			// Close it
-			if m.OverlayManager != nil {
-				m.OverlayManager.PopOverlay()
-			}
-			m.OpenMenuIndex = -1
+			m.closeMenu()
 		} else {
-			// If another menu is open, close it first?
-			// If OverlayManager has a stack, we might need to pop the previous one if it's a sibling menu.
-			// But Master handles "click outside" which might have already popped it?
-			// If we clicked on this menu item, we are outside the previous popup.
-			// So Master might have popped it.
-			// But we need to be sure.
-			// If m.OpenMenuIndex != -1, it means we think a menu is open.
-			// If Master popped it, our OnDismiss should have cleared OpenMenuIndex.
-			// So if OpenMenuIndex is still set, we should pop it.
-			if m.OpenMenuIndex != -1 {
-				if m.OverlayManager != nil {
-					m.OverlayManager.PopOverlay()
-				}
-			}
+			m.openMenu(index)
+		}
+	}
+}
 
-			m.OpenMenuIndex = index
-			if m.OverlayManager != nil {
-				// Position popup
-				menuItem := m.Menus[index]
-				popup := m.Popups[index]
-				sz := popup.MinSize()
-				popup.SetRect(q2d.Rectangle{
-					menuItem.Rect.X(),
-					menuItem.Rect.Y() + menuItem.Rect.Height(),
-					sz.Width,
-					sz.Height,
-				})
-
-				// Set dismiss callback
-				popup.OnDismissFunc = func() {
-					m.OpenMenuIndex = -1
-				}
+func (m *MenuBar) openMenu(index int) {
+	// If Master popped the open menu, its OnDismiss has already cleared
+	// OpenMenuIndex
+	if m.OpenMenuIndex != -1 {
+		m.Popups[m.OpenMenuIndex].Close()
+	}
 
-				m.OverlayManager.PushOverlay(popup)
-			}
+	m.OpenMenuIndex = index
+	m.highlight = index
+	if m.OverlayManager != nil {
+		// Position popup
+		menuItem := m.Menus[index]
+		popup := m.Popups[index]
+		attachOverlay(m.OverlayManager, popup)
+		sz := popup.MinSize()
+		popup.SetRect(placePopup(sz, menuItem.Rect, screenSize(m.OverlayManager), false))
+		popup.Layout(sz)
+
+		// Set dismiss callback
+		popup.OnDismissFunc = func() {
+			m.OpenMenuIndex = -1
+		}
+		// Choosing a command ends keyboard navigation
+		popup.onActivate = func() {
+			m.active = false
+		}
+
+		m.OverlayManager.PushOverlay(popup)
+	}
+}
+
+// openMenuFromKeyboard opens the menu at index with its first item
+// highlighted.
+func (m *MenuBar) openMenuFromKeyboard(index int) {
+	m.openMenu(index)
+	if m.OpenMenuIndex == index {
+		m.Popups[index].highlight(nil)
+		m.Popups[index].moveHighlight(1)
+	}
+}
+
+func (m *MenuBar) closeMenu() {
+	if m.OpenMenuIndex != -1 {
+		m.Popups[m.OpenMenuIndex].Close()
+	}
+	m.OpenMenuIndex = -1
+}
+
+// Deactivate closes the open menu and ends keyboard navigation.
+func (m *MenuBar) Deactivate() {
+	m.active = false
+	m.closeMenu()
+}
+
+// mnemonicIndex returns the index of the menu with the given mnemonic key, or
+// -1.
+func (m *MenuBar) mnemonicIndex(key int) int {
+	r := mnemonicKey(key)
+	for i, item := range m.Menus {
+		if mn := item.Mnemonic(); mn != 0 && mn == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleKey implements keyboard access to the menus. Master calls it for key
+// events that no open popup has handled.
+func (m *MenuBar) HandleKey(e KeyEvent) bool {
+	n := len(m.Menus)
+	if n == 0 {
+		return false
+	}
+
+	// Alt activates the bar when released without another key in between,
+	// so that chords such as Alt+Tab leave it alone
+	if e.Key == KeyAlt {
+		switch {
+		case e.TypeVal == EventKeyUp && m.altDown:
+			m.altDown = false
+			m.active = true
+			m.highlight = 0
+			return true
+		case e.TypeVal == EventKeyDown && (m.active || m.OpenMenuIndex != -1):
+			m.altDown = false
+			m.Deactivate()
+			return true
+		case e.TypeVal == EventKeyDown:
+			m.altDown = true
+		}
+		return false
+	}
+	if e.TypeVal != EventKeyDown {
+		return false
+	}
+	m.altDown = false
+	if e.Mods&ModAlt != 0 {
+		if i := m.mnemonicIndex(e.Key); i >= 0 {
+			m.active = true
+			m.openMenuFromKeyboard(i)
+			return true
+		}
+		m.active = false
+		return false
+	}
+
+	if m.OpenMenuIndex != -1 {
+		// Left and Right unused by the open popup move between menus
+		switch e.Key {
+		case KeyLeft:
+			m.openMenuFromKeyboard((m.OpenMenuIndex - 1 + n) % n)
+		case KeyRight:
+			m.openMenuFromKeyboard((m.OpenMenuIndex + 1) % n)
+		default:
+			return false
 		}
+		return true
+	}
+
+	if !m.active {
+		return false
 	}
+	switch e.Key {
+	case KeyLeft:
+		m.highlight = (m.highlight - 1 + n) % n
+	case KeyRight:
+		m.highlight = (m.highlight + 1) % n
+	case KeyEnter, KeySpace, KeyDown:
+		m.openMenuFromKeyboard(m.highlight)
+	case KeyEscape:
+		m.active = false
+	default:
+		if i := m.mnemonicIndex(e.Key); i >= 0 {
+			m.openMenuFromKeyboard(i)
+		}
+	}
+	// Keyboard navigation swallows all other keys
+	return true
 }
 
 func (m *MenuBar) MinSize() Size {
@@ -286,7 +909,8 @@ func (m *MenuBar) MinSize() Size {
 		return Size{0, 0}
 	}
 	metrics := theme.Font.Metrics()
-	height := (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
+	pad := theme.Style(StyleMenuItem, 0).Padding
+	height := (metrics.Ascent + metrics.Descent).Ceil() + pad.Top + pad.Bottom
 
 	// Width is 100% usually, but min width is sum of items
 	w := 0
@@ -317,30 +941,15 @@ func (m *MenuBar) Layout(available Size) Size {
 func (m *MenuBar) Draw(img *q2d.Image) {
 	theme := m.GetTheme()
 	img.PushSubImage(m.Rect)
-	img.Fill(theme.BackgroundColor.Darken(0.1))
+	DrawBox(img, q2d.Rectangle{0, 0, m.Rect.Width(), m.Rect.Height()}, theme.Style(StyleMenuBar, m.state()))
 	img.PopSubImage()
 
-	for _, item := range m.Menus {
+	for i, item := range m.Menus {
+		item.highlighted = i == m.OpenMenuIndex || (m.active && i == m.highlight)
 		item.Draw(img)
 	}
 }
 
-func (m *MenuBar) Event(e Event) bool {
-	// Popups are handled by Master now.
-	// We just handle menu bar clicks.
-
-	handled := false
-	for _, item := range m.Menus {
-		if item.Event(e) {
-			handled = true
-			if e.Type() != EventMouseMove {
-				return true
-			}
-		}
-	}
-	return handled
-}
-
 func (m *MenuBar) FindWidgetAt(pos q2d.Point) Widget {
 	if !m.Rect.Contains(pos) {
 		return nil
@@ -0,0 +1,66 @@
+package qui
+
+import "testing"
+
+func keyDown(key int, mods KeyMod) KeyEvent {
+	return KeyEvent{TypeVal: EventKeyDown, Key: key, Mods: mods}
+}
+
+func keyUp(key int, mods KeyMod) KeyEvent {
+	return KeyEvent{TypeVal: EventKeyUp, Key: key, Mods: mods}
+}
+
+// tapAlt presses and releases Alt on its own.
+var tapAlt = []KeyEvent{keyDown(KeyAlt, ModAlt), keyUp(KeyAlt, 0)}
+
+// altChord holds Alt while pressing key.
+func altChord(key int) []KeyEvent {
+	return []KeyEvent{
+		keyDown(KeyAlt, ModAlt),
+		keyDown(key, ModAlt),
+		keyUp(key, ModAlt),
+		keyUp(KeyAlt, 0),
+	}
+}
+
+func TestMenuBarAltKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   [][]KeyEvent
+		active bool
+		open   int
+	}{
+		{"tap Alt", [][]KeyEvent{tapAlt}, true, -1},
+		{"tap Alt twice", [][]KeyEvent{tapAlt, tapAlt}, false, -1},
+		{"Alt+Tab", [][]KeyEvent{altChord(KeyTab)}, false, -1},
+		{"Alt+F4", [][]KeyEvent{altChord(KeyF1 + 3)}, false, -1},
+		{"Alt+Shift", [][]KeyEvent{altChord(KeyShift)}, false, -1},
+		{"Alt+Q is not a mnemonic", [][]KeyEvent{altChord('Q')}, false, -1},
+		{"tap Alt then Alt+Q", [][]KeyEvent{tapAlt, altChord('Q')}, false, -1},
+		{"Alt+mnemonic", [][]KeyEvent{altChord('E')}, true, 1},
+		{"tap Alt then mnemonic", [][]KeyEvent{tapAlt, {keyDown('E', 0)}}, true, 1},
+		{"Alt closes an open menu", [][]KeyEvent{altChord('F'), tapAlt}, false, -1},
+	}
+	for _, tt := range tests {
+		bar := NewMenuBar()
+		bar.AddMenu("&File", NewPopupMenu(NewMenuItem("&Open", IconNone, nil)))
+		bar.AddMenu("&Edit", NewPopupMenu(NewMenuItem("&Copy", IconNone, nil)))
+		m := NewMaster(NewContainer(LayoutVertical, bar), nil)
+		m.MenuBar = bar
+		bar.OverlayManager = m
+		for _, keys := range tt.keys {
+			for _, k := range keys {
+				m.Event(k)
+			}
+		}
+		if bar.active != tt.active || bar.OpenMenuIndex != tt.open {
+			t.Errorf("%s: active %v with menu %d open, want %v with %d", tt.name, bar.active, bar.OpenMenuIndex, tt.active, tt.open)
+		}
+		if !tt.active {
+			// Keys and shortcuts reach the rest of the UI again
+			if bar.HandleKey(keyDown('A', 0)) {
+				t.Errorf("%s: the menu bar swallowed a key", tt.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package qui
+
+import "github.com/qbradq/q2d"
+
+// NavAction is a directional navigation input, such as from a gamepad.
+type NavAction int
+
+const (
+	NavUp NavAction = iota
+	NavDown
+	NavLeft
+	NavRight
+	// NavConfirm activates the focused widget.
+	NavConfirm
+	// NavBack closes the top overlay.
+	NavBack
+)
+
+// NavEvent is sent to the Master for navigation input. It goes to the
+// focused widget first, and if that does not consume it the Master moves the
+// focus, see Master.Navigate.
+type NavEvent struct {
+	Action NavAction
+}
+
+func (e NavEvent) Type() EventType { return EventNavigate }
+
+// Activatable is implemented by widgets that do something when confirmed
+// with NavConfirm, like a button being clicked.
+type Activatable interface {
+	Activate()
+}
+
+// navKeys maps keys to navigation in spatial navigation mode.
+var navKeys = map[int]NavAction{
+	KeyUp:     NavUp,
+	KeyDown:   NavDown,
+	KeyLeft:   NavLeft,
+	KeyRight:  NavRight,
+	KeyEnter:  NavConfirm,
+	KeySpace:  NavConfirm,
+	KeyEscape: NavBack,
+}
+
+// SetNeighbor makes NavUp, NavDown, NavLeft or NavRight move the focus from
+// the widget to w rather than to the nearest widget in that direction.
+func (b *BaseWidget) SetNeighbor(a NavAction, w Widget) {
+	if b.neighbors == nil {
+		b.neighbors = make(map[NavAction]Widget)
+	}
+	if w == nil {
+		delete(b.neighbors, a)
+		return
+	}
+	b.neighbors[a] = w
+}
+
+// Neighbor returns the widget set with SetNeighbor, or nil.
+func (b *BaseWidget) Neighbor(a NavAction) Widget {
+	return b.neighbors[a]
+}
+
+// Navigate performs a navigation action. Directions move the focus to the
+// nearest focusable widget that way in the top overlay, or the root if there
+// are none. NavConfirm activates the focused widget and NavBack closes the
+// top overlay. It returns true if anything happened.
+func (m *Master) Navigate(a NavAction) bool {
+	switch a {
+	case NavConfirm:
+		w, ok := m.FocusedWidget.(Widget)
+		if !ok || !interactive(w) {
+			return false
+		}
+		if act, ok := w.(Activatable); ok {
+			act.Activate()
+			return true
+		}
+		return false
+	case NavBack:
+		if len(m.Overlays) == 0 {
+			return false
+		}
+		m.PopOverlay()
+		return true
+	}
+
+	var from Widget
+	if w, ok := m.FocusedWidget.(Widget); ok && interactive(w) {
+		from = w
+	}
+	if from != nil {
+		if n, ok := from.(interface{ Neighbor(NavAction) Widget }); ok {
+			if to := n.Neighbor(a); to != nil {
+				if f, ok := to.(Focusable); ok && interactive(to) {
+					m.SetFocus(f)
+					return true
+				}
+			}
+		}
+	}
+
+	candidates := m.navCandidates()
+	if len(candidates) == 0 {
+		return false
+	}
+	if from == nil {
+		// Nothing focused yet, start at the first widget
+		m.SetFocus(candidates[0].(Focusable))
+		return true
+	}
+	var best Widget
+	bestScore := 0
+	for _, c := range candidates {
+		if c == from {
+			continue
+		}
+		if score, ok := navScore(navRect(from), navRect(c), a); ok && (best == nil || score < bestScore) {
+			best, bestScore = c, score
+		}
+	}
+	if best == nil {
+		return false
+	}
+	m.SetFocus(best.(Focusable))
+	return true
+}
+
+// navCandidates returns the focusable widgets that can be navigated to, in
+// the order of Walk.
+func (m *Master) navCandidates() []Widget {
+	scope := m.Root
+	if len(m.Overlays) > 0 {
+		scope = m.Overlays[len(m.Overlays)-1]
+	}
+	if scope == nil {
+		return nil
+	}
+	return FindWidgets(scope, func(w Widget) bool {
+		if _, ok := w.(Focusable); !ok || !interactive(w) {
+			return false
+		}
+		// Only widgets that are actually on screen, not those in inactive
+		// tabs or covered by overlays
+		r := navRect(w)
+		if r.Width() <= 0 || r.Height() <= 0 {
+			return false
+		}
+		return m.widgetAt(rectCenter(r)) == w
+	})
+}
+
+// navRect returns the part of w that takes the focus, such as the tab headers
+// of a TabContainer.
+func navRect(w Widget) q2d.Rectangle {
+	if n, ok := w.(interface{ navRect() q2d.Rectangle }); ok {
+		return n.navRect()
+	}
+	return w.GetRect()
+}
+
+func rectCenter(r q2d.Rectangle) q2d.Point {
+	return q2d.Point{r.X() + r.Width()/2, r.Y() + r.Height()/2}
+}
+
+// navScore rates how good a move from one rectangle to another is in
+// direction a, lower being better. It returns false if to is not that way.
+func navScore(from, to q2d.Rectangle, a NavAction) (int, bool) {
+	fc, tc := rectCenter(from), rectCenter(to)
+	// Distance along the direction of travel, and the misalignment across it
+	var major, minor int
+	switch a {
+	case NavLeft, NavRight:
+		if a == NavRight {
+			if tc.X() <= fc.X() {
+				return 0, false
+			}
+			major = to.X() - (from.X() + from.Width())
+		} else {
+			if tc.X() >= fc.X() {
+				return 0, false
+			}
+			major = from.X() - (to.X() + to.Width())
+		}
+		minor = rangeGap(from.Y(), from.Height(), to.Y(), to.Height())
+	default:
+		if a == NavDown {
+			if tc.Y() <= fc.Y() {
+				return 0, false
+			}
+			major = to.Y() - (from.Y() + from.Height())
+		} else {
+			if tc.Y() >= fc.Y() {
+				return 0, false
+			}
+			major = from.Y() - (to.Y() + to.Height())
+		}
+		minor = rangeGap(from.X(), from.Width(), to.X(), to.Width())
+	}
+	// Widgets in line win over closer ones off to the side
+	return max(major, 0) + 2*minor, true
+}
+
+// rangeGap returns the distance between two ranges, 0 if they overlap.
+func rangeGap(a, alen, b, blen int) int {
+	switch {
+	case b >= a+alen:
+		return b - (a + alen)
+	case a >= b+blen:
+		return a - (b + blen)
+	}
+	return 0
+}
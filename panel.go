@@ -10,9 +10,18 @@ type Panel struct {
 }
 
 func NewPanel(content Widget) *Panel {
-	return &Panel{
+	p := &Panel{
 		Content: content,
 	}
+	adopt(p, content)
+	return p
+}
+
+func (p *Panel) GetChildren() []Widget {
+	if p.Content == nil {
+		return nil
+	}
+	return []Widget{p.Content}
 }
 
 func (p *Panel) MinSize() Size {
@@ -20,7 +29,7 @@ func (p *Panel) MinSize() Size {
 	if theme == nil {
 		return Size{0, 0}
 	}
-	if p.Content == nil {
+	if !shown(p.Content) {
 		return Size{theme.Padding.Left + theme.Padding.Right, theme.Padding.Top + theme.Padding.Bottom}
 	}
 	sz := p.Content.MinSize()
@@ -33,7 +42,8 @@ func (p *Panel) Layout(available Size) Size {
 		return available
 	}
 
-	if p.Content != nil {
+	adopt(p, p.Content)
+	if shown(p.Content) {
 		p.Content.SetRect(q2d.Rectangle{
 			p.Rect.X() + theme.Padding.Left,
 			p.Rect.Y() + theme.Padding.Top,
@@ -57,30 +67,18 @@ func (p *Panel) Draw(img *q2d.Image) {
 	img.PushSubImage(p.Rect)
 	defer img.PopSubImage()
 
-	if theme.BackgroundColor.A() > 0 {
-		img.Fill(theme.BackgroundColor)
-	}
-	if theme.BorderColor.A() > 0 {
-		img.Border(theme.BorderColor)
-	}
+	DrawBox(img, q2d.Rectangle{0, 0, p.Rect.Width(), p.Rect.Height()}, theme.Style(StylePanel, p.state()))
 
-	if p.Content != nil {
+	if shown(p.Content) {
 		p.Content.Draw(img)
 	}
 }
 
-func (p *Panel) Event(evt Event) bool {
-	if p.Content != nil {
-		return p.Content.Event(evt)
-	}
-	return false
-}
-
 func (p *Panel) FindWidgetAt(pos q2d.Point) Widget {
 	if !p.Rect.Contains(pos) {
 		return nil
 	}
-	if p.Content != nil {
+	if shown(p.Content) {
 		if found := p.Content.FindWidgetAt(pos); found != nil {
 			return found
 		}
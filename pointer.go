@@ -0,0 +1,44 @@
+package qui
+
+// PointerGrabber is implemented by widgets that track drags themselves, such
+// as buttons waiting for the release of a press or windows being moved. After
+// a press Master captures the pointer to the innermost widget under it whose
+// GrabsPointer returns true, until it returns false or the button is released.
+type PointerGrabber interface {
+	GrabsPointer() bool
+}
+
+// CapturePointer sends all mouse events to w, wherever the pointer is, until
+// the mouse button is released or ReleasePointer is called. The hovered widget
+// does not change in the meantime.
+func (m *Master) CapturePointer(w Widget) {
+	m.capture = w
+	m.captureImplicit = false
+}
+
+// ReleasePointer ends a pointer capture.
+func (m *Master) ReleasePointer() {
+	if m.capture == nil {
+		return
+	}
+	m.capture = nil
+	m.captureImplicit = false
+	m.UpdateHover(m.MousePos)
+}
+
+// PointerCapture returns the widget the pointer is captured to, or nil.
+func (m *Master) PointerCapture() Widget {
+	return m.capture
+}
+
+// grabPointer captures the pointer for the innermost widget at or above w
+// that has started a drag.
+func (m *Master) grabPointer(w Widget) {
+	for ; w != nil; w = parentOf(w) {
+		if g, ok := w.(PointerGrabber); ok && g.GrabsPointer() {
+			m.capture = w
+			m.captureImplicit = true
+			return
+		}
+	}
+}
@@ -22,14 +22,68 @@ type OverlayManager interface {
 	PopOverlay()
 }
 
+// OverlayStack is implemented by overlay managers that can remove overlays
+// from anywhere in their stack and know the size of the screen, as Master
+// does. Submenus, floating dock panels and maximized windows need it. With
+// other overlay managers closing an overlay pops the top one and popups are
+// not kept on the screen.
+type OverlayStack interface {
+	OverlayManager
+	// RemoveOverlay removes w from the overlay stack wherever it is.
+	RemoveOverlay(w Widget)
+	// ScreenSize returns the size of the area overlays are shown in.
+	ScreenSize() Size
+}
+
+// removeOverlay removes w from om, or pops the top overlay if om is not an
+// OverlayStack.
+func removeOverlay(om OverlayManager, w Widget) {
+	if s, ok := om.(OverlayStack); ok {
+		s.RemoveOverlay(w)
+		return
+	}
+	om.PopOverlay()
+}
+
+// screenSize returns the screen size of om, or a zero Size if om is not an
+// OverlayStack.
+func screenSize(om OverlayManager) Size {
+	if s, ok := om.(OverlayStack); ok {
+		return s.ScreenSize()
+	}
+	return Size{}
+}
+
 type Dismissable interface {
 	OnDismiss()
 }
 
+// Modeless is implemented by overlays that stay open when the user clicks
+// outside of them, such as floating tool windows.
+type Modeless interface {
+	IsModeless() bool
+}
+
 type ManagedOverlay interface {
 	SetOverlayManager(m OverlayManager)
 }
 
+// ContextMenuProvider is implemented by widgets that open a PopupMenu when
+// right-clicked. Returning nil means there is no menu at pos.
+type ContextMenuProvider interface {
+	ContextMenuAt(pos q2d.Point) *PopupMenu
+}
+
+// Animator is implemented by widgets that move on their own, such as a
+// ScrolledContainer scrolling on after a fling. Master steps them before each
+// frame is drawn, see Master.Animating.
+type Animator interface {
+	// Animating returns true while the widget has frames left to show.
+	Animating() bool
+	// StepAnimation advances the animation to the current time.
+	StepAnimation()
+}
+
 type Focusable interface {
 	Focus()
 	Unfocus()
@@ -44,7 +98,8 @@ type Widget interface {
 	Draw(img *q2d.Image)
 
 	// Event handles input events (mouse, keyboard).
-	// Returns true if the event was consumed.
+	// Returns true if the event was consumed. Events that are not consumed
+	// are offered to the widget's parent next.
 	Event(e Event) bool
 
 	// MinSize returns the minimum size required by the widget.
@@ -59,24 +114,80 @@ type Widget interface {
 	// GetTooltip returns the tooltip text.
 	GetTooltip() string
 
-	// GetTheme returns the widget's theme or the default theme.
+	// GetTheme returns the widget's theme merged over the theme of its
+	// parent, see Theme.Merge.
 	GetTheme() *Theme
-	// SetTheme sets the widget's theme.
+	// SetTheme sets the widget's theme. Only its fields that are not zero
+	// override the inherited theme.
 	SetTheme(t *Theme)
 
-	// FindWidgetAt returns the widget at the given position, or nil.
+	// FindWidgetAt returns the widget at the given position, or nil. Hidden
+	// widgets are never found.
 	FindWidgetAt(pos q2d.Point) Widget
 
 	// IsFill returns true if the widget should fill available space in its parent container.
 	IsFill() bool
 }
 
+// Node is implemented by widgets that take part in the widget tree, as all
+// widgets embedding BaseWidget do. Widgets that do not implement it are
+// treated as enabled and visible leaves with no parent, ID, classes or event
+// handlers.
+type Node interface {
+	Widget
+
+	// IsDisabled returns true if the widget ignores input and cannot be
+	// focused. Disabled widgets are drawn with the theme's disabled colors.
+	IsDisabled() bool
+
+	// IsHidden returns true if the widget is neither drawn nor laid out.
+	IsHidden() bool
+
+	// GetParent returns the widget containing this one, or nil for the root
+	// and overlays.
+	GetParent() Widget
+	// SetParent is called by containers when the widget is added to them.
+	SetParent(p Widget)
+	// GetChildren returns the widgets directly contained in this one.
+	GetChildren() []Widget
+
+	// GetID returns the widget's ID, which may be empty.
+	GetID() string
+	// HasClass returns true if the widget has the style class c.
+	HasClass(c string) bool
+
+	// Handlers returns the event handlers subscribed to the widget. They run
+	// before the widget's own Event handling, see EventContext.
+	Handlers() *EventHandlers
+}
+
 // BaseWidget can be embedded to provide common functionality
 type BaseWidget struct {
+	// ID identifies the widget for Master.FindByID and selectors. IDs should
+	// be unique but this is not enforced.
+	ID string
+	// Classes are style classes matched by .class selectors.
+	Classes []string
 	Rect    q2d.Rectangle
 	Tooltip string
 	Theme   *Theme
 	Fill    bool
+	// Disabled widgets are drawn dimmed and ignore input.
+	Disabled bool
+	// Hidden widgets take no space and are not drawn.
+	Hidden bool
+	// ContextMenu is opened at the cursor when the widget is right-clicked.
+	ContextMenu *PopupMenu
+
+	parent    Widget
+	master    *Master // Master showing the widget as a root or overlay
+	handlers  EventHandlers
+	neighbors map[NavAction]Widget // See SetNeighbor
+
+	// Theme merged by GetTheme, and the themes it was merged from
+	merged     *Theme
+	mergedFrom *Theme
+	mergedOwn  *Theme
 }
 
 func (b *BaseWidget) SetRect(r q2d.Rectangle) {
@@ -107,15 +218,36 @@ func (b *BaseWidget) GetTooltip() string {
 	return b.Tooltip
 }
 
+// GetTheme returns the theme inherited from the widget's parent, with the
+// widget's own theme merged over it. Widgets without a parent inherit the
+// theme of the Master showing them, or DefaultTheme if there is none. The
+// merged theme is kept until either theme changes. Call SetTheme again after
+// changing fields of the widget's own theme.
 func (b *BaseWidget) GetTheme() *Theme {
-	if b.Theme != nil {
-		return b.Theme
+	inherited := DefaultTheme
+	if b.parent != nil {
+		inherited = b.parent.GetTheme()
+	} else if b.master != nil && b.master.currentTheme() != nil {
+		inherited = b.master.currentTheme()
+	}
+	if b.Theme == nil {
+		return inherited
 	}
-	return DefaultTheme
+	if b.merged == nil || b.mergedFrom != inherited || b.mergedOwn != b.Theme {
+		b.merged = inherited.Merge(inherited.scaleOverride(b.Theme))
+		b.mergedFrom, b.mergedOwn = inherited, b.Theme
+	}
+	return b.merged
 }
 
 func (b *BaseWidget) SetTheme(t *Theme) {
 	b.Theme = t
+	b.clearTheme()
+}
+
+// clearTheme drops the theme merged by GetTheme.
+func (b *BaseWidget) clearTheme() {
+	b.merged, b.mergedFrom, b.mergedOwn = nil, nil, nil
 }
 
 func (b *BaseWidget) FindWidgetAt(pos q2d.Point) Widget {
@@ -128,3 +260,98 @@ func (b *BaseWidget) FindWidgetAt(pos q2d.Point) Widget {
 func (b *BaseWidget) IsFill() bool {
 	return b.Fill
 }
+
+func (b *BaseWidget) IsDisabled() bool {
+	return b.Disabled
+}
+
+func (b *BaseWidget) IsHidden() bool {
+	return b.Hidden
+}
+
+func (b *BaseWidget) GetParent() Widget {
+	return b.parent
+}
+
+func (b *BaseWidget) SetParent(p Widget) {
+	b.parent = p
+	b.clearTheme()
+}
+
+func (b *BaseWidget) setMaster(m *Master) {
+	b.master = m
+}
+
+func (b *BaseWidget) GetChildren() []Widget {
+	return nil
+}
+
+func (b *BaseWidget) Handlers() *EventHandlers {
+	return &b.handlers
+}
+
+func (b *BaseWidget) GetID() string {
+	return b.ID
+}
+
+func (b *BaseWidget) HasClass(c string) bool {
+	for _, class := range b.Classes {
+		if class == c {
+			return true
+		}
+	}
+	return false
+}
+
+// disabled returns true if the widget or one of its ancestors is disabled.
+func (b *BaseWidget) disabled() bool {
+	if b.Disabled {
+		return true
+	}
+	for p := b.parent; p != nil; p = parentOf(p) {
+		if isDisabled(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// textColor returns the text color of the widget for its disabled state.
+func (b *BaseWidget) textColor(theme *Theme) q2d.Color {
+	if b.disabled() {
+		return theme.DisabledTextColor
+	}
+	return theme.TextColor
+}
+
+// state returns the style state common to all widgets, that is whether the
+// widget is disabled.
+func (b *BaseWidget) state() StyleState {
+	if b.disabled() {
+		return StateDisabled
+	}
+	return 0
+}
+
+// shown returns true if w is set and not hidden.
+func shown(w Widget) bool {
+	return w != nil && !isHidden(w)
+}
+
+// interactive returns true if w is set, visible and enabled, and so are all of
+// its ancestors.
+func interactive(w Widget) bool {
+	if w == nil {
+		return false
+	}
+	for ; w != nil; w = parentOf(w) {
+		if isHidden(w) || isDisabled(w) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BaseWidget) ContextMenuAt(pos q2d.Point) *PopupMenu {
+	return b.ContextMenu
+}
@@ -54,19 +54,21 @@ func NewRadioButton(label, value string, group *RadioGroup) *RadioButton {
 }
 
 func (r *RadioButton) MinSize() Size {
-	if DefaultTheme == nil || DefaultTheme.Font == nil {
+	theme := r.GetTheme()
+	if theme == nil || theme.Font == nil {
 		return Size{0, 0}
 	}
-	width := IconSize + DefaultTheme.Spacing
-	width += font.MeasureString(DefaultTheme.Font, r.Label).Ceil()
+	width := theme.IconSize() + theme.Spacing
+	width += font.MeasureString(theme.Font, r.Label).Ceil()
 
-	metrics := DefaultTheme.Font.Metrics()
+	metrics := theme.Font.Metrics()
 	height := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > height {
-		height = IconSize
+	if theme.IconSize() > height {
+		height = theme.IconSize()
 	}
 
-	return Size{width + (DefaultTheme.Padding.Left + DefaultTheme.Padding.Right), height + (DefaultTheme.Padding.Top + DefaultTheme.Padding.Bottom)}
+	pad := theme.Style(StyleCheck, 0).Padding
+	return Size{width + pad.Left + pad.Right, height + pad.Top + pad.Bottom}
 }
 
 func (r *RadioButton) Event(e Event) bool {
@@ -74,6 +76,11 @@ func (r *RadioButton) Event(e Event) bool {
 	case MouseEvent:
 		inRect := r.Rect.Contains(evt.Pos)
 
+		if evt.TypeVal == EventMouseLeave {
+			r.hovered = false
+			return false
+		}
+
 		if evt.TypeVal == EventMouseMove {
 			wasHovered := r.hovered
 			r.hovered = inRect
@@ -109,6 +116,11 @@ func (r *RadioButton) Select() {
 	}
 }
 
+// Activate selects the radio button.
+func (r *RadioButton) Activate() {
+	r.Select()
+}
+
 func (r *RadioButton) Focus() {
 	r.focused = true
 }
@@ -124,17 +136,34 @@ func (r *RadioButton) FindWidgetAt(pos q2d.Point) Widget {
 	return nil
 }
 
+func (r *RadioButton) GrabsPointer() bool {
+	return r.pressed
+}
+
 func (r *RadioButton) Draw(img *q2d.Image) {
-	if DefaultTheme == nil {
+	theme := r.GetTheme()
+	if theme == nil {
 		return
 	}
 
 	img.PushSubImage(r.Rect)
 	defer img.PopSubImage()
 
+	state := r.state()
+	if r.hovered {
+		state |= StateHovered
+	}
+	if r.pressed {
+		state |= StatePressed
+	}
 	if r.focused {
-		img.Fill(DefaultTheme.BackgroundColor.Lighten(0.1))
+		state |= StateFocused
+	}
+	if r.Group != nil && r.Group.SelectedValue == r.Value {
+		state |= StateSelected
 	}
+	st := theme.Style(StyleCheck, state)
+	DrawBox(img, q2d.Rectangle{0, 0, r.Rect.Width(), r.Rect.Height()}, st)
 
 	// Draw Icon
 	icon := IconRadioOff
@@ -142,11 +171,11 @@ func (r *RadioButton) Draw(img *q2d.Image) {
 		icon = IconRadioOn
 	}
 
-	metrics := DefaultTheme.Font.Metrics()
+	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	contentHeight := textHeight
-	if IconSize > contentHeight {
-		contentHeight = IconSize
+	if theme.IconSize() > contentHeight {
+		contentHeight = theme.IconSize()
 	}
 
 	y := (r.Rect.Height() - contentHeight) / 2
@@ -154,10 +183,10 @@ func (r *RadioButton) Draw(img *q2d.Image) {
 		y = 0
 	}
 
-	iconY := y + (contentHeight-IconSize)/2
-	DrawIcon(img, icon, q2d.Point{DefaultTheme.Padding.Left, iconY}, DefaultTheme.TextColor)
+	iconY := y + (contentHeight-theme.IconSize())/2
+	theme.DrawIcon(img, icon, q2d.Point{st.Padding.Left, iconY}, st.Text)
 
-	textX := DefaultTheme.Padding.Left + IconSize + DefaultTheme.Spacing
+	textX := st.Padding.Left + theme.IconSize() + theme.Spacing
 	textY := y + (contentHeight-textHeight)/2
-	img.Text(q2d.Point{textX, textY}, DefaultTheme.TextColor, DefaultTheme.Font, false, "%s", r.Label)
+	img.Text(q2d.Point{textX, textY}, st.Text, theme.Font, false, "%s", r.Label)
 }
@@ -0,0 +1,169 @@
+package qui
+
+import (
+	"math"
+
+	"golang.org/x/image/font"
+)
+
+// SetScale sets the factor the UI is scaled by, such as 1.5 or 2 for high
+// DPI screens. Fonts loaded from files, padding, spacing, styles, icons and
+// the sizes of scroll bars, frames and pointer thresholds are scaled, and
+// the UI is laid out again. Widgets are told as by SetTheme, with Old and New
+// both the theme of m. Fonts set without a FontFile are kept at their size.
+func (m *Master) SetScale(f float64) {
+	if f <= 0 {
+		f = 1
+	}
+	if f == m.Scale() {
+		return
+	}
+	m.scale = f
+	m.scaled = nil
+	m.notifyTheme(m.Theme, m.Theme)
+	if m.size != (Size{}) {
+		m.Layout(m.size)
+	}
+}
+
+// Scale returns the factor the UI is scaled by, 1 unless set by SetScale.
+func (m *Master) Scale() float64 {
+	if m.scale == 0 {
+		return 1
+	}
+	return m.scale
+}
+
+// px returns v pixels at the scale of m.
+func (m *Master) px(v int) int {
+	return scalePx(v, m.Scale())
+}
+
+// scaledTheme returns t at the scale of m. The scaled Theme is kept until the
+// theme or scale change.
+func (m *Master) scaledTheme(t *Theme) *Theme {
+	if t == nil || m.Scale() == 1 {
+		return t
+	}
+	if m.scaled != nil && m.scaledFrom == t {
+		return m.scaled
+	}
+	if m.fonts == nil {
+		m.fonts = make(fontCache)
+	}
+	m.scaled = t.scaled(m.Scale(), m.fonts)
+	m.scaledFrom = t
+	return m.scaled
+}
+
+// fontKey identifies a font file loaded at a size.
+type fontKey struct {
+	file string
+	size float64
+}
+
+// fontCache holds the faces loaded for scaled themes. Each Master has its own
+// as faces are not safe for concurrent use.
+type fontCache map[fontKey]font.Face
+
+// Scale returns the factor the theme is scaled by. Themes of widgets shown by
+// a Master are scaled by Master.Scale, see Master.SetScale.
+func (t *Theme) Scale() float64 {
+	if t == nil || t.scale == 0 {
+		return 1
+	}
+	return t.scale
+}
+
+// Px returns a length of v pixels at the scale of the theme, for custom
+// widgets with sizes of their own. Lengths that are not zero stay at least a
+// pixel long.
+func (t *Theme) Px(v int) int {
+	return scalePx(v, t.Scale())
+}
+
+// IconSize returns the size icons are drawn at, which is the IconSize
+// constant at the scale of the theme.
+func (t *Theme) IconSize() int {
+	return t.Px(IconSize)
+}
+
+// scaled returns a copy of t with its metrics multiplied by f. The font is
+// loaded again at the scaled size if FontFile is set. Zero values stay zero
+// so that partial themes still merge.
+func (t *Theme) scaled(f float64, fonts fontCache) *Theme {
+	if t == nil {
+		return nil
+	}
+	ret := *t
+	ret.scale = f
+	ret.fonts = fonts
+	ret.Spacing = scalePx(t.Spacing, f)
+	ret.Padding = scalePadding(t.Padding, f)
+	ret.Radius = scalePx(t.Radius, f)
+	if t.BorderWidth > 0 {
+		ret.BorderWidth = scalePx(t.BorderWidth, f)
+	}
+	ret.Shadow.OffsetX = scalePx(t.Shadow.OffsetX, f)
+	ret.Shadow.OffsetY = scalePx(t.Shadow.OffsetY, f)
+	ret.Shadow.Blur = scalePx(t.Shadow.Blur, f)
+	for k := range styleKindKeys {
+		s := ret.Styles.kind(StyleKind(k))
+		for _, state := range styleStates {
+			st := s.style(state)
+			st.Padding = scalePadding(st.Padding, f)
+			st.Radius = scalePx(st.Radius, f)
+			if st.BorderWidth > 0 {
+				st.BorderWidth = scalePx(st.BorderWidth, f)
+			}
+		}
+	}
+	if t.FontFile != "" && t.FontSize > 0 && f != 1 {
+		key := fontKey{t.FontFile, t.FontSize * f}
+		face := fonts[key]
+		if face == nil {
+			var err error
+			if face, err = loadFontFile(key.file, key.size); err == nil && fonts != nil {
+				fonts[key] = face
+			}
+		}
+		if face != nil {
+			ret.Font = face
+		}
+	}
+	return &ret
+}
+
+// scalePx returns v multiplied by f, rounded so that lengths that are not
+// zero do not vanish.
+func scalePx(v int, f float64) int {
+	if v == 0 || f == 1 {
+		return v
+	}
+	r := int(math.Round(float64(v) * f))
+	switch {
+	case r == 0 && v > 0:
+		return 1
+	case r == 0 && v < 0:
+		return -1
+	}
+	return r
+}
+
+func scalePadding(p Padding, f float64) Padding {
+	return Padding{
+		Top:    scalePx(p.Top, f),
+		Right:  scalePx(p.Right, f),
+		Bottom: scalePx(p.Bottom, f),
+		Left:   scalePx(p.Left, f),
+	}
+}
+
+// scaleOverride returns the partial theme o at the scale of t, for merging
+// over t.
+func (t *Theme) scaleOverride(o *Theme) *Theme {
+	if o == nil || t.Scale() == 1 {
+		return o
+	}
+	return o.scaled(t.Scale(), t.fonts)
+}
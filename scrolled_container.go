@@ -1,9 +1,24 @@
 package qui
 
 import (
+	"math"
+	"time"
+
 	"github.com/qbradq/q2d"
 )
 
+// KineticFriction is how quickly a ScrolledContainer flung by a pan slows
+// down. Higher values stop it sooner.
+var KineticFriction = 4.0
+
+const (
+	// scrollBarSize is the width of scroll bars, here and in List and
+	// SelectList, before scaling.
+	scrollBarSize = 10
+	// minThumbSize is the least length of the thumb of a scroll bar.
+	minThumbSize = 20
+)
+
 type ScrolledContainer struct {
 	BaseWidget
 	Content Widget
@@ -17,26 +32,42 @@ type ScrolledContainer struct {
 	dragStart    q2d.Point
 	startScrollX int
 	startScrollY int
+
+	// Touch panning and kinetic scrolling
+	panning              bool
+	velocityX, velocityY float64 // Pixels per second
+	restX, restY         float64 // Fractions of pixels not scrolled yet
+	lastStep             time.Time
 }
 
 func NewScrolledContainer(content Widget) *ScrolledContainer {
-	return &ScrolledContainer{
+	s := &ScrolledContainer{
 		Content: content,
 	}
+	adopt(s, content)
+	return s
+}
+
+func (s *ScrolledContainer) GetChildren() []Widget {
+	if s.Content == nil {
+		return nil
+	}
+	return []Widget{s.Content}
 }
 
 func (s *ScrolledContainer) MinSize() Size {
-	w := 100
-	h := 100
+	theme := s.GetTheme()
+	w := theme.Px(100)
+	h := theme.Px(100)
 	if s.Width > 0 {
 		w = s.Width
 	} else {
 		// If no fixed width, try to fit content width + scrollbar
-		if s.Content != nil {
+		if shown(s.Content) {
 			sz := s.Content.MinSize()
-			w = sz.Width + 10
+			w = sz.Width + theme.Px(scrollBarSize)
 		} else {
-			w = 10
+			w = theme.Px(scrollBarSize)
 		}
 	}
 
@@ -52,7 +83,7 @@ func (s *ScrolledContainer) Layout(available Size) Size {
 	viewportH := s.Rect.Height()
 
 	var contentMin Size
-	if s.Content != nil {
+	if shown(s.Content) {
 		contentMin = s.Content.MinSize()
 	}
 
@@ -61,7 +92,8 @@ func (s *ScrolledContainer) Layout(available Size) Size {
 	needV := contentMin.Height > viewportH
 	needH := contentMin.Width > viewportW
 
-	barSize := 10
+	theme := s.GetTheme()
+	barSize := theme.Px(scrollBarSize)
 
 	// If we need V, width reduces
 	if needV {
@@ -111,7 +143,8 @@ func (s *ScrolledContainer) Layout(available Size) Size {
 		contentH = viewportH
 	}
 
-	if s.Content != nil {
+	adopt(s, s.Content)
+	if shown(s.Content) {
 		s.Content.SetRect(q2d.Rectangle{
 			s.Rect.X() - s.ScrollX,
 			s.Rect.Y() - s.ScrollY,
@@ -125,10 +158,14 @@ func (s *ScrolledContainer) Layout(available Size) Size {
 }
 
 func (s *ScrolledContainer) Event(evt Event) bool {
-	barSize := 10
+	if s.Disabled {
+		return false
+	}
+	theme := s.GetTheme()
+	barSize := theme.Px(scrollBarSize)
 
 	var contentMin Size
-	if s.Content != nil {
+	if shown(s.Content) {
 		contentMin = s.Content.MinSize()
 	}
 	viewportW := s.Rect.Width()
@@ -163,8 +200,34 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 	}
 
 	switch event := evt.(type) {
+	case TouchEvent:
+		// Touching stops kinetic scrolling
+		if event.TypeVal == EventTouchBegin && s.Rect.Contains(event.Pos) {
+			s.velocityX, s.velocityY = 0, 0
+		}
+
+	case GestureEvent:
+		if event.TypeVal != EventPan {
+			break
+		}
+		if event.State == GestureBegan {
+			s.panning = s.Rect.Contains(event.Pos) && (maxScrollX > 0 || maxScrollY > 0)
+		}
+		if !s.panning {
+			break
+		}
+		s.scrollBy(-float64(event.Delta.X()), -float64(event.Delta.Y()), maxScrollX, maxScrollY)
+		if event.State == GestureEnded {
+			s.velocityX, s.velocityY = -event.VelocityX, -event.VelocityY
+			s.lastStep = time.Now()
+		}
+		if event.State >= GestureEnded {
+			s.panning = false
+		}
+		return true
+
 	case ScrollEvent:
-		s.ScrollY -= int(event.DeltaY * 20)
+		s.ScrollY -= int(event.DeltaY * float64(theme.Px(20)))
 		if s.ScrollY < 0 {
 			s.ScrollY = 0
 		}
@@ -185,8 +248,8 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 					// In vertical bar area
 					trackH := effViewportH
 					thumbH := int(float64(trackH) * float64(trackH) / float64(contentMin.Height))
-					if thumbH < 20 {
-						thumbH = 20
+					if thumbH < theme.Px(minThumbSize) {
+						thumbH = theme.Px(minThumbSize)
 					}
 					thumbY := int(float64(s.ScrollY) / float64(maxScrollY) * float64(trackH-thumbH))
 
@@ -203,8 +266,8 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 					// In horizontal bar area
 					trackW := effViewportW
 					thumbW := int(float64(trackW) * float64(trackW) / float64(contentMin.Width))
-					if thumbW < 20 {
-						thumbW = 20
+					if thumbW < theme.Px(minThumbSize) {
+						thumbW = theme.Px(minThumbSize)
 					}
 					thumbX := int(float64(s.ScrollX) / float64(maxScrollX) * float64(trackW-thumbW))
 
@@ -215,29 +278,19 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 						return true
 					}
 				}
-
-				// Pass to content
-				if s.Content != nil {
-					return s.Content.Event(evt)
-				}
-				return false
 			}
 		} else if event.TypeVal == EventMouseUp {
+			dragged := s.draggingX || s.draggingY
 			s.draggingX = false
 			s.draggingY = false
-			if s.Rect.Contains(event.Pos) {
-				if s.Content != nil {
-					return s.Content.Event(evt)
-				}
-				return false
-			}
+			return dragged
 		} else if event.TypeVal == EventMouseMove {
 			if s.draggingY && maxScrollY > 0 {
 				deltaY := event.Pos.Y() - s.dragStart.Y()
 				trackH := effViewportH
 				thumbH := int(float64(trackH) * float64(trackH) / float64(contentMin.Height))
-				if thumbH < 20 {
-					thumbH = 20
+				if thumbH < theme.Px(minThumbSize) {
+					thumbH = theme.Px(minThumbSize)
 				}
 				// deltaY corresponds to how much scroll?
 				// thumb moves (trackH - thumbH) for maxScrollY
@@ -255,8 +308,8 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 				deltaX := event.Pos.X() - s.dragStart.X()
 				trackW := effViewportW
 				thumbW := int(float64(trackW) * float64(trackW) / float64(contentMin.Width))
-				if thumbW < 20 {
-					thumbW = 20
+				if thumbW < theme.Px(minThumbSize) {
+					thumbW = theme.Px(minThumbSize)
 				}
 				scrollDelta := int(float64(deltaX) * float64(maxScrollX) / float64(trackW-thumbW))
 				s.ScrollX = s.startScrollX + scrollDelta
@@ -269,43 +322,103 @@ func (s *ScrolledContainer) Event(evt Event) bool {
 				return true
 			}
 
-			if s.Rect.Contains(event.Pos) {
-				if s.Content != nil {
-					s.Content.Event(evt)
-				}
-				// Always return true if hovering?
-				// Maybe not, but we want to track hover.
-				return true
-			}
+			// Moves over the viewport stop here
+			return s.Rect.Contains(event.Pos)
 		}
 	}
-	if s.Content != nil {
-		return s.Content.Event(evt)
-	}
 	return false
 }
 
+// scrollBy scrolls by dx and dy pixels, keeping fractions of pixels for the
+// next call. It stops kinetic scrolling along an axis when it hits the end.
+func (s *ScrolledContainer) scrollBy(dx, dy float64, maxScrollX, maxScrollY int) {
+	s.restX += dx
+	s.restY += dy
+	x := s.ScrollX + int(s.restX)
+	y := s.ScrollY + int(s.restY)
+	s.restX -= math.Trunc(s.restX)
+	s.restY -= math.Trunc(s.restY)
+	if x <= 0 && dx < 0 || x >= maxScrollX && dx > 0 {
+		s.velocityX, s.restX = 0, 0
+	}
+	if y <= 0 && dy < 0 || y >= maxScrollY && dy > 0 {
+		s.velocityY, s.restY = 0, 0
+	}
+	s.ScrollX = min(max(x, 0), maxScrollX)
+	s.ScrollY = min(max(y, 0), maxScrollY)
+	s.Layout(Size{s.Rect.Width(), s.Rect.Height()})
+}
+
+// maxScroll returns how far the content can be scrolled.
+func (s *ScrolledContainer) maxScroll() (int, int) {
+	var contentMin Size
+	if shown(s.Content) {
+		contentMin = s.Content.MinSize()
+	}
+	vp := s.viewport()
+	return max(contentMin.Width-vp.Width(), 0), max(contentMin.Height-vp.Height(), 0)
+}
+
+// viewport returns the absolute rectangle the content shows in, which is the
+// container without its scroll bars.
+func (s *ScrolledContainer) viewport() q2d.Rectangle {
+	barSize := s.GetTheme().Px(scrollBarSize)
+	var contentMin Size
+	if shown(s.Content) {
+		contentMin = s.Content.MinSize()
+	}
+	viewportW := s.Rect.Width()
+	viewportH := s.Rect.Height()
+	needV := contentMin.Height > viewportH
+	needH := contentMin.Width > viewportW
+	if needV && contentMin.Width > viewportW-barSize {
+		needH = true
+	}
+	if needH && contentMin.Height > viewportH-barSize {
+		needV = true
+	}
+	if needV {
+		viewportW -= barSize
+	}
+	if needH {
+		viewportH -= barSize
+	}
+	return q2d.Rectangle{s.Rect.X(), s.Rect.Y(), viewportW, viewportH}
+}
+
+// Animating returns true while the container keeps scrolling after a pan.
+func (s *ScrolledContainer) Animating() bool {
+	return s.velocityX != 0 || s.velocityY != 0
+}
+
+// StepAnimation keeps scrolling after a pan ends, slowing down until it
+// stops.
+func (s *ScrolledContainer) StepAnimation() {
+	if !s.Animating() {
+		return
+	}
+	now := time.Now()
+	dt := now.Sub(s.lastStep).Seconds()
+	s.lastStep = now
+	maxScrollX, maxScrollY := s.maxScroll()
+	s.scrollBy(s.velocityX*dt, s.velocityY*dt, maxScrollX, maxScrollY)
+	decay := math.Exp(-KineticFriction * dt)
+	s.velocityX *= decay
+	s.velocityY *= decay
+	if math.Hypot(s.velocityX, s.velocityY) < 10 {
+		s.velocityX, s.velocityY = 0, 0
+	}
+}
+
 func (s *ScrolledContainer) FindWidgetAt(pos q2d.Point) Widget {
 	if !s.Rect.Contains(pos) {
 		return nil
 	}
-	// Check content (which is clipped, so we should check clip?)
-	// Content rect is offset.
-	// If pos is inside container rect, we check content.
-	// But content might be larger.
-	// If we are over scrollbars, we should return ScrolledContainer (to handle drag).
-	// But FindWidgetAt is for tooltips.
-	// If we hover scrollbar, maybe no tooltip?
-	// Or ScrolledContainer tooltip?
-
-	// Check if over scrollbars
-	// We need to recalculate scrollbar areas or store them.
-	// For simplicity, let's check content first.
-	// But content is offset.
-	// FindWidgetAt expects absolute pos.
-	// Content has absolute pos set during layout.
-
-	if s.Content != nil {
+	// The scroll bars cover the content
+	if !s.viewport().Contains(pos) {
+		return s
+	}
+	if shown(s.Content) {
 		if found := s.Content.FindWidgetAt(pos); found != nil {
 			// But wait, content is clipped by ScrolledContainer rect.
 			// If found widget is outside ScrolledContainer rect (but inside Content rect),
@@ -319,21 +432,26 @@ func (s *ScrolledContainer) FindWidgetAt(pos q2d.Point) Widget {
 	return s
 }
 
+func (s *ScrolledContainer) GrabsPointer() bool {
+	return s.draggingX || s.draggingY
+}
+
 func (s *ScrolledContainer) Draw(img *q2d.Image) {
 	img.PushClip(s.Rect)
 	defer img.PopClip()
 
-	if s.Content != nil {
+	if shown(s.Content) {
 		s.Content.Draw(img)
 	}
 
 	var contentMin Size
-	if s.Content != nil {
+	if shown(s.Content) {
 		contentMin = s.Content.MinSize()
 	}
 	viewportW := s.Rect.Width()
 	viewportH := s.Rect.Height()
-	barSize := 10
+	theme := s.GetTheme()
+	barSize := theme.Px(scrollBarSize)
 
 	needV := contentMin.Height > viewportH
 	needH := contentMin.Width > viewportW
@@ -360,8 +478,8 @@ func (s *ScrolledContainer) Draw(img *q2d.Image) {
 	if needV {
 		trackH := effViewportH
 		thumbH := int(float64(trackH) * float64(trackH) / float64(contentMin.Height))
-		if thumbH < 20 {
-			thumbH = 20
+		if thumbH < theme.Px(minThumbSize) {
+			thumbH = theme.Px(minThumbSize)
 		}
 		maxScrollY := contentMin.Height - effViewportH
 		thumbY := 0
@@ -369,28 +487,18 @@ func (s *ScrolledContainer) Draw(img *q2d.Image) {
 			thumbY = int(float64(s.ScrollY) / float64(maxScrollY) * float64(trackH-thumbH))
 		}
 
-		theme := s.GetTheme()
-		if theme == nil {
-			theme = DefaultTheme
-		}
-
-		// Track
-		img.PushSubImage(q2d.Rectangle{viewportW - barSize, 0, barSize, trackH})
-		img.Fill(theme.BackgroundColor.Lighten(0.1))
-		img.PopSubImage()
-
-		// Thumb
-		img.PushSubImage(q2d.Rectangle{viewportW - barSize, thumbY, barSize, thumbH})
-		img.Fill(theme.BorderColor)
-		img.PopSubImage()
+		drawScrollBar(img, theme,
+			q2d.Rectangle{viewportW - barSize, 0, barSize, trackH},
+			q2d.Rectangle{viewportW - barSize, thumbY, barSize, thumbH},
+			s.draggingY)
 	}
 
 	// Draw Horizontal Scrollbar
 	if needH {
 		trackW := effViewportW
 		thumbW := int(float64(trackW) * float64(trackW) / float64(contentMin.Width))
-		if thumbW < 20 {
-			thumbW = 20
+		if thumbW < theme.Px(minThumbSize) {
+			thumbW = theme.Px(minThumbSize)
 		}
 		maxScrollX := contentMin.Width - effViewportW
 		thumbX := 0
@@ -398,30 +506,25 @@ func (s *ScrolledContainer) Draw(img *q2d.Image) {
 			thumbX = int(float64(s.ScrollX) / float64(maxScrollX) * float64(trackW-thumbW))
 		}
 
-		theme := s.GetTheme()
-		if theme == nil {
-			theme = DefaultTheme
-		}
-
-		// Track
-		img.PushSubImage(q2d.Rectangle{0, viewportH - barSize, trackW, barSize})
-		img.Fill(theme.BackgroundColor.Lighten(0.1))
-		img.PopSubImage()
-
-		// Thumb
-		img.PushSubImage(q2d.Rectangle{thumbX, viewportH - barSize, thumbW, barSize})
-		img.Fill(theme.BorderColor)
-		img.PopSubImage()
+		drawScrollBar(img, theme,
+			q2d.Rectangle{0, viewportH - barSize, trackW, barSize},
+			q2d.Rectangle{thumbX, viewportH - barSize, thumbW, barSize},
+			s.draggingX)
 	}
 
 	// Corner
 	if needV && needH {
-		theme := s.GetTheme()
-		if theme == nil {
-			theme = DefaultTheme
-		}
-		img.PushSubImage(q2d.Rectangle{viewportW - barSize, viewportH - barSize, barSize, barSize})
-		img.Fill(theme.BackgroundColor.Darken(0.3))
-		img.PopSubImage()
+		DrawBox(img, q2d.Rectangle{viewportW - barSize, viewportH - barSize, barSize, barSize}, theme.Style(StyleScrollBar, 0))
+	}
+}
+
+// drawScrollBar draws a scroll bar track and its thumb, relative to the
+// current sub image. The thumb is drawn pressed while it is dragged.
+func drawScrollBar(img *q2d.Image, theme *Theme, track, thumb q2d.Rectangle, dragging bool) {
+	DrawBox(img, track, theme.Style(StyleScrollBar, 0))
+	var state StyleState
+	if dragging {
+		state = StatePressed
 	}
+	DrawBox(img, thumb, theme.Style(StyleScrollThumb, state))
 }
@@ -13,6 +13,7 @@ type Select struct {
 
 	expanded     bool
 	hoveredIndex int
+	focused      bool
 
 	OverlayManager OverlayManager
 }
@@ -34,85 +35,174 @@ func (s *Select) MinSize() Size {
 	maxWidth := 0
 	metrics := theme.Font.Metrics()
 	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
 	for _, item := range s.Items {
 		w := font.MeasureString(theme.Font, item.Text).Ceil()
 		if item.Icon != IconNone {
-			w += IconSize + theme.Spacing
+			w += theme.IconSize() + theme.Spacing
 		}
 		if w > maxWidth {
 			maxWidth = w
 		}
 	}
 
-	h := lineHeight + theme.Padding.Top + theme.Padding.Bottom
+	pad := theme.Style(StyleSelect, 0).Padding
+	h := lineHeight + pad.Top + pad.Bottom
 	// No longer include list height in MinSize as it's an overlay
 
-	return Size{maxWidth + (theme.Padding.Left+theme.Padding.Right)*2, h}
+	return Size{maxWidth + (pad.Left+pad.Right)*2, h}
 }
 
 func (s *Select) Event(evt Event) bool {
-	theme := s.GetTheme()
-	if theme == nil || theme.Font == nil {
-		return false
-	}
-
 	switch event := evt.(type) {
 	case MouseEvent:
-		if s.Rect.Contains(event.Pos) {
-			if event.TypeVal == EventMouseDown {
-				// Toggle
-				if s.expanded {
-					// Close
-					if s.OverlayManager != nil {
-						s.OverlayManager.PopOverlay()
-					}
-					s.expanded = false
-				} else {
-					// Open
-					s.expanded = true
-					if s.OverlayManager != nil {
-						// Create and push overlay
-						list := &SelectList{
-							Select: s,
-							OnDismissFunc: func() {
-								s.expanded = false
-							},
-						}
-						// Calculate size and pos
-						metrics := theme.Font.Metrics()
-						lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-						if IconSize > lineHeight {
-							lineHeight = IconSize
-						}
-						lineHeight += 2
-
-						h := len(s.Items)*lineHeight + 2
-						if len(s.Items) > 5 {
-							h = 5*lineHeight + 2
-						}
-
-						list.SetRect(q2d.Rectangle{
-							s.Rect.X(),
-							s.Rect.Y() + s.Rect.Height(),
-							s.Rect.Width(),
-							h,
-						})
-
-						s.OverlayManager.PushOverlay(list)
-					}
-				}
+		if s.Rect.Contains(event.Pos) && event.TypeVal == EventMouseDown {
+			if s.expanded {
+				s.close()
+			} else {
+				s.open()
+			}
+			return true
+		}
+	case KeyEvent:
+		if !s.focused || event.TypeVal != EventKeyDown {
+			break
+		}
+		switch event.Key {
+		case KeyEnter, KeySpace:
+			s.Activate()
+			return true
+		case KeyUp:
+			return s.moveHover(-1)
+		case KeyDown:
+			return s.moveHover(1)
+		case KeyEscape:
+			if s.expanded {
+				s.close()
 				return true
 			}
 		}
+	case NavEvent:
+		if !s.expanded {
+			break
+		}
+		switch event.Action {
+		case NavUp:
+			return s.moveHover(-1)
+		case NavDown:
+			return s.moveHover(1)
+		case NavBack:
+			s.close()
+			return true
+		}
 	}
 	return false
 }
 
+// Activate opens the list of items, or chooses the hovered item if it is
+// open.
+func (s *Select) Activate() {
+	if !s.expanded {
+		s.open()
+		return
+	}
+	if i := s.hoveredIndex; i >= 0 && i < len(s.Items) {
+		s.choose(i)
+		return
+	}
+	s.close()
+}
+
+// moveHover moves the hovered item of the open list by delta. It returns
+// false if the list is closed.
+func (s *Select) moveHover(delta int) bool {
+	if !s.expanded || len(s.Items) == 0 {
+		return false
+	}
+	i := s.hoveredIndex
+	if i < 0 {
+		i = s.SelectedIndex
+	}
+	s.hoveredIndex = min(max(i+delta, 0), len(s.Items)-1)
+	return true
+}
+
+// choose selects the item at index and closes the list.
+func (s *Select) choose(index int) {
+	s.SelectedIndex = index
+	if s.OnSelect != nil {
+		s.OnSelect(index)
+	}
+	s.close()
+}
+
+// open shows the list of items below the select.
+func (s *Select) open() {
+	theme := s.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return
+	}
+	s.expanded = true
+	s.hoveredIndex = -1
+	if s.OverlayManager == nil {
+		return
+	}
+	list := &SelectList{
+		Select: s,
+		OnDismissFunc: func() {
+			s.expanded = false
+		},
+	}
+	// Calculate size and pos
+	metrics := theme.Font.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
+	}
+	lineHeight += theme.Px(2)
+
+	h := len(s.Items)*lineHeight + 2
+	if len(s.Items) > 5 {
+		h = 5*lineHeight + 2
+	}
+
+	list.SetRect(q2d.Rectangle{
+		s.Rect.X(),
+		s.Rect.Y() + s.Rect.Height(),
+		s.Rect.Width(),
+		h,
+	})
+
+	s.OverlayManager.PushOverlay(list)
+}
+
+// close hides the list of items.
+func (s *Select) close() {
+	if s.expanded && s.OverlayManager != nil {
+		s.OverlayManager.PopOverlay()
+	}
+	s.expanded = false
+}
+
+func (s *Select) Focus() {
+	s.focused = true
+}
+
+func (s *Select) Unfocus() {
+	s.focused = false
+}
+
+func (s *Select) FindWidgetAt(pos q2d.Point) Widget {
+	if s.Rect.Contains(pos) {
+		return s
+	}
+	return nil
+}
+
 func (s *Select) Draw(img *q2d.Image) {
 	theme := s.GetTheme()
 	if theme == nil {
@@ -125,15 +215,22 @@ func (s *Select) Draw(img *q2d.Image) {
 	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	lineHeight := textHeight
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
+	}
+	lineHeight += theme.Px(2)
+	state := s.state()
+	if s.expanded {
+		state |= StatePressed
 	}
-	lineHeight += 2
-	headerHeight := lineHeight + theme.Padding.Top + theme.Padding.Bottom
+	if s.focused {
+		state |= StateFocused
+	}
+	st := theme.Style(StyleSelect, state)
+	headerHeight := lineHeight + st.Padding.Top + st.Padding.Bottom
 
 	// Draw Header
-	img.Fill(theme.ButtonColor)
-	img.Border(theme.BorderColor)
+	DrawBox(img, q2d.Rectangle{0, 0, s.Rect.Width(), s.Rect.Height()}, st)
 
 	text := "Select..."
 	icon := IconNone
@@ -142,15 +239,15 @@ func (s *Select) Draw(img *q2d.Image) {
 		icon = s.Items[s.SelectedIndex].Icon
 	}
 
-	x := theme.Padding.Left
+	x := st.Padding.Left
 	if icon != IconNone {
-		iconY := (headerHeight - IconSize) / 2
-		DrawIcon(img, icon, q2d.Point{x, iconY}, theme.TextColor)
-		x += IconSize + theme.Spacing
+		iconY := (headerHeight - theme.IconSize()) / 2
+		theme.DrawIcon(img, icon, q2d.Point{x, iconY}, st.Text)
+		x += theme.IconSize() + theme.Spacing
 	}
 
 	textY := (headerHeight - textHeight) / 2
-	img.Text(q2d.Point{x, textY}, theme.TextColor, theme.Font, false, "%s", text)
+	img.Text(q2d.Point{x, textY}, st.Text, theme.Font, false, "%s", text)
 }
 
 type SelectList struct {
@@ -177,12 +274,12 @@ func (l *SelectList) Event(evt Event) bool {
 	}
 	metrics := theme.Font.Metrics()
 	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
-	barSize := 10
+	barSize := theme.Px(scrollBarSize)
 	contentHeight := len(l.Select.Items) * lineHeight
 	viewportHeight := l.Rect.Height() - 2
 	maxScroll := contentHeight - viewportHeight
@@ -215,14 +312,7 @@ func (l *SelectList) Event(evt Event) bool {
 				index := relY / lineHeight
 
 				if index >= 0 && index < len(l.Select.Items) {
-					l.Select.SelectedIndex = index
-					if l.Select.OnSelect != nil {
-						l.Select.OnSelect(index)
-					}
-					// Close overlay
-					if l.Select.OverlayManager != nil {
-						l.Select.OverlayManager.PopOverlay()
-					}
+					l.Select.choose(index)
 					return true
 				}
 			}
@@ -236,8 +326,8 @@ func (l *SelectList) Event(evt Event) bool {
 				deltaY := event.Pos.Y() - l.dragStart.Y()
 				trackH := viewportHeight
 				thumbH := int(float64(trackH) * float64(trackH) / float64(contentHeight))
-				if thumbH < 20 {
-					thumbH = 20
+				if thumbH < theme.Px(minThumbSize) {
+					thumbH = theme.Px(minThumbSize)
 				}
 
 				scrollDelta := int(float64(deltaY) * float64(maxScroll) / float64(trackH-thumbH))
@@ -270,32 +360,47 @@ func (l *SelectList) Event(evt Event) bool {
 			} else {
 				l.Select.hoveredIndex = -1
 			}
+		} else if event.TypeVal == EventMouseLeave {
+			l.Select.hoveredIndex = -1
 		}
 	}
 	return false
 }
 
+func (l *SelectList) GrabsPointer() bool {
+	return l.dragging
+}
+
+func (l *SelectList) FindWidgetAt(pos q2d.Point) Widget {
+	if l.Rect.Contains(pos) {
+		return l
+	}
+	return nil
+}
+
 func (l *SelectList) Draw(img *q2d.Image) {
 	theme := l.Select.GetTheme()
 	if theme == nil {
 		return
 	}
 
+	box := theme.Style(StyleList, 0)
+	DrawShadow(img, l.Rect, box.Radius, theme.Shadow)
+
 	img.PushSubImage(l.Rect)
 	defer img.PopSubImage()
 
-	img.Fill(theme.BackgroundColor)
-	img.Border(theme.BorderColor)
+	DrawBox(img, q2d.Rectangle{0, 0, l.Rect.Width(), l.Rect.Height()}, box)
 
 	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 	lineHeight := textHeight
-	if IconSize > lineHeight {
-		lineHeight = IconSize
+	if theme.IconSize() > lineHeight {
+		lineHeight = theme.IconSize()
 	}
-	lineHeight += 2
+	lineHeight += theme.Px(2)
 
-	barSize := 10
+	barSize := theme.Px(scrollBarSize)
 	viewportHeight := l.Rect.Height() - 2
 	contentHeight := len(l.Select.Items) * lineHeight
 	maxScroll := contentHeight - viewportHeight
@@ -323,28 +428,25 @@ func (l *SelectList) Draw(img *q2d.Image) {
 		item := l.Select.Items[i]
 		y := i*lineHeight - l.ScrollOffset
 
-		bg := theme.BackgroundColor
+		var state StyleState
 		if i == l.Select.SelectedIndex {
-			bg = theme.PrimaryColor
-		} else if i == l.Select.hoveredIndex {
-			bg = theme.SecondaryColor
+			state |= StateSelected
 		}
-
-		if i == l.Select.SelectedIndex || i == l.Select.hoveredIndex {
-			img.PushSubImage(q2d.Rectangle{0, y, contentRect.Width(), lineHeight})
-			img.Fill(bg)
-			img.PopSubImage()
+		if i == l.Select.hoveredIndex {
+			state |= StateHovered
 		}
+		st := theme.Style(StyleListItem, state)
+		DrawBox(img, q2d.Rectangle{0, y, contentRect.Width(), lineHeight}, st)
 
-		itemX := theme.Padding.Left
+		itemX := st.Padding.Left
 		if item.Icon != IconNone {
-			iconY := y + (lineHeight-IconSize)/2
-			DrawIcon(img, item.Icon, q2d.Point{itemX, iconY}, theme.TextColor)
-			itemX += IconSize + theme.Spacing
+			iconY := y + (lineHeight-theme.IconSize())/2
+			theme.DrawIcon(img, item.Icon, q2d.Point{itemX, iconY}, st.Text)
+			itemX += theme.IconSize() + theme.Spacing
 		}
 
 		itemTextY := y + (lineHeight-textHeight)/2
-		img.Text(q2d.Point{itemX, itemTextY}, theme.TextColor, theme.Font, false, "%s", item.Text)
+		img.Text(q2d.Point{itemX, itemTextY}, st.Text, theme.Font, false, "%s", item.Text)
 	}
 	img.PopSubImage() // Pop content clip
 
@@ -352,19 +454,14 @@ func (l *SelectList) Draw(img *q2d.Image) {
 	if maxScroll > 0 {
 		trackH := viewportHeight
 		thumbH := int(float64(trackH) * float64(trackH) / float64(contentHeight))
-		if thumbH < 20 {
-			thumbH = 20
+		if thumbH < theme.Px(minThumbSize) {
+			thumbH = theme.Px(minThumbSize)
 		}
 		thumbY := int(float64(l.ScrollOffset) / float64(maxScroll) * float64(trackH-thumbH))
 
-		// Track
-		img.PushSubImage(q2d.Rectangle{l.Rect.Width() - barSize - 1, 1, barSize, trackH})
-		img.Fill(theme.BackgroundColor.Lighten(0.1)) // Lighter track
-		img.PopSubImage()
-
-		// Thumb
-		img.PushSubImage(q2d.Rectangle{l.Rect.Width() - barSize - 1, 1 + thumbY, barSize, thumbH})
-		img.Fill(theme.BorderColor)
-		img.PopSubImage()
+		drawScrollBar(img, theme,
+			q2d.Rectangle{l.Rect.Width() - barSize - 1, 1, barSize, trackH},
+			q2d.Rectangle{l.Rect.Width() - barSize - 1, 1 + thumbY, barSize, thumbH},
+			l.dragging)
 	}
 }
@@ -0,0 +1,133 @@
+package qui
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+)
+
+// textSpan is the range of bytes [start, end) of a string.
+type textSpan struct {
+	start, end int
+}
+
+// wrappedLine is a line of text wrapped by wrapWords.
+type wrappedLine struct {
+	para  textSpan   // Paragraph the line belongs to
+	words []textSpan // Words on the line
+}
+
+// text returns the line as drawn, its words separated by single spaces.
+func (l wrappedLine) text(s string) string {
+	parts := make([]string, len(l.words))
+	for i, w := range l.words {
+		parts[i] = s[w.start:w.end]
+	}
+	return strings.Join(parts, " ")
+}
+
+// wordX returns the offset of word i from the start of the line.
+func (l wrappedLine) wordX(s string, f font.Face, i int) int {
+	if i == 0 {
+		return 0
+	}
+	prefix := wrappedLine{words: l.words[:i]}.text(s) + " "
+	return font.MeasureString(f, prefix).Ceil()
+}
+
+// wrapWords splits text into lines no wider than maxWidth the way q2d does
+// when drawing wrapped text, keeping track of where the words came from.
+func wrapWords(text string, f font.Face, maxWidth int) []wrappedLine {
+	var lines []wrappedLine
+	offset := 0
+	for _, paragraph := range strings.Split(text, "\n") {
+		para := textSpan{offset, offset + len(paragraph)}
+		offset = para.end + 1
+		var words []textSpan
+		start := -1
+		for i, r := range paragraph {
+			switch {
+			case unicode.IsSpace(r) && start >= 0:
+				words = append(words, textSpan{para.start + start, para.start + i})
+				start = -1
+			case !unicode.IsSpace(r) && start < 0:
+				start = i
+			}
+		}
+		if start >= 0 {
+			words = append(words, textSpan{para.start + start, para.end})
+		}
+		if len(words) == 0 {
+			lines = append(lines, wrappedLine{para: para})
+			continue
+		}
+		first, line := 0, text[words[0].start:words[0].end]
+		for i := 1; i < len(words); i++ {
+			word := text[words[i].start:words[i].end]
+			if font.MeasureString(f, line+" "+word).Ceil() <= maxWidth {
+				line += " " + word
+				continue
+			}
+			lines = append(lines, wrappedLine{para: para, words: words[first:i]})
+			first, line = i, word
+		}
+		lines = append(lines, wrappedLine{para: para, words: words[first:]})
+	}
+	return lines
+}
+
+// runeClass groups runes for word selection: spaces, word characters and
+// everything else.
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	}
+	return 2
+}
+
+// wordBounds returns the run of runes of the same class as the rune at i,
+// which is the word a double-click at i selects.
+func wordBounds(runes []rune, i int) (int, int) {
+	if len(runes) == 0 {
+		return 0, 0
+	}
+	i = min(max(i, 0), len(runes)-1)
+	class := runeClass(runes[i])
+	start, end := i, i+1
+	for start > 0 && runeClass(runes[start-1]) == class {
+		start--
+	}
+	for end < len(runes) && runeClass(runes[end]) == class {
+		end++
+	}
+	return start, end
+}
+
+// runeIndexAt returns the boundary between runes closest to x pixels from the
+// start of text.
+func runeIndexAt(runes []rune, f font.Face, x int) int {
+	prev := 0
+	for i := range runes {
+		next := font.MeasureString(f, string(runes[:i+1])).Ceil()
+		if x < (prev+next)/2 {
+			return i
+		}
+		prev = next
+	}
+	return len(runes)
+}
+
+// runeAt returns the index of the rune x pixels from the start of text, or
+// len(runes) past its end.
+func runeAt(runes []rune, f font.Face, x int) int {
+	for i := range runes {
+		if x < font.MeasureString(f, string(runes[:i+1])).Ceil() {
+			return i
+		}
+	}
+	return len(runes)
+}
@@ -0,0 +1,126 @@
+package qui
+
+import (
+	"testing"
+
+	"github.com/qbradq/q2d"
+	"golang.org/x/image/font/basicfont"
+)
+
+func testTheme() *Theme {
+	return GenerateThemeFromColor(q2d.Color{0, 120, 215, 255}, basicfont.Face7x13)
+}
+
+func TestWordBounds(t *testing.T) {
+	tests := []struct {
+		text       string
+		i          int
+		start, end int
+	}{
+		{"hello, world", 0, 0, 5},
+		{"hello, world", 4, 0, 5},
+		{"hello, world", 5, 5, 6},
+		{"hello, world", 6, 6, 7},
+		{"hello, world", 9, 7, 12},
+		{"hello, world", 12, 7, 12},
+		{"snake_case2 x", 3, 0, 11},
+		{"a  b", 2, 1, 3},
+		{"", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		start, end := wordBounds([]rune(tt.text), tt.i)
+		if start != tt.start || end != tt.end {
+			t.Errorf("wordBounds(%q, %d) = %d, %d, want %d, %d", tt.text, tt.i, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestEntrySelection(t *testing.T) {
+	// Each glyph of Face7x13 is 7 pixels wide
+	tests := []struct {
+		name   string
+		typ    EntryType
+		x      int
+		clicks int
+		want   string
+	}{
+		{"single click", EntryText, 52, 1, ""},
+		{"word", EntryText, 52, 2, "world"},
+		{"first word", EntryText, 3, 2, "hello"},
+		{"punctuation", EntryText, 38, 2, ","},
+		{"past the end", EntryText, 150, 2, "world"},
+		{"line", EntryText, 52, 3, "hello, world"},
+		{"password", EntryPassword, 52, 2, "hello, world"},
+	}
+	for _, tt := range tests {
+		e := NewEntry("hello, world", tt.typ)
+		e.SetTheme(testTheme())
+		e.Rect = q2d.Rectangle{0, 0, 200, 20}
+		e.Input.Rect = q2d.Rectangle{0, 0, 200, 13}
+		e.Event(MouseEvent{TypeVal: EventMouseDown, Pos: q2d.Point{tt.x, 5}, Clicks: tt.clicks})
+		if got := e.Input.SelectedText(); got != tt.want {
+			t.Errorf("%s: selected %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextInputReplacesSelection(t *testing.T) {
+	in := NewTextInput("hello, world", EntryText)
+	in.SetTheme(testTheme())
+	in.Rect = q2d.Rectangle{0, 0, 200, 13}
+	in.Focus()
+	in.Event(MouseEvent{TypeVal: EventMouseDown, Pos: q2d.Point{52, 5}, Clicks: 2})
+	in.Event(TextInputEvent{Text: "there"})
+	if in.Text != "hello, there" || in.SelectedText() != "" {
+		t.Errorf("typing over the selection: text %q, selected %q", in.Text, in.SelectedText())
+	}
+	in.Event(MouseEvent{TypeVal: EventMouseDown, Pos: q2d.Point{3, 5}, Clicks: 2})
+	in.Event(KeyEvent{TypeVal: EventKeyDown, Key: KeyBackspace})
+	if in.Text != ", there" {
+		t.Errorf("backspace over the selection: text %q", in.Text)
+	}
+
+	num := NewTextInput("123", EntryInteger)
+	num.SetTheme(testTheme())
+	num.Rect = q2d.Rectangle{0, 0, 200, 13}
+	num.Focus()
+	num.Event(MouseEvent{TypeVal: EventMouseDown, Pos: q2d.Point{3, 5}, Clicks: 3})
+	num.Event(TextInputEvent{Text: "x"})
+	if num.Text != "123" || num.SelectedText() != "123" {
+		t.Errorf("invalid text over the selection: text %q, selected %q", num.Text, num.SelectedText())
+	}
+}
+
+func TestTextAreaSelection(t *testing.T) {
+	theme := testTheme()
+	metrics := theme.Font.Metrics()
+	lh := (metrics.Ascent + metrics.Descent).Ceil()
+	tests := []struct {
+		name     string
+		width    int // Wrap width besides the padding
+		row, col int // Glyph col of wrapped line row
+		clicks   int
+		want     string
+	}{
+		{"single click", 200, 2, 7, 1, ""},
+		{"word", 200, 2, 7, 2, "four"},
+		{"word on the first line", 200, 0, 1, 2, "one"},
+		{"between words", 200, 0, 3, 2, "one"},
+		{"line", 200, 2, 1, 3, "three four"},
+		{"below the text", 200, 5, 1, 3, "three four"},
+		{"empty line", 200, 1, 1, 3, ""},
+		{"wrapped word", 30, 1, 1, 2, "two"},
+		{"wrapped line", 30, 1, 1, 3, "one two"},
+	}
+	for _, tt := range tests {
+		ta := NewTextArea("one two\n\nthree four")
+		ta.SetTheme(theme)
+		pad := ta.style(theme).Padding
+		ta.Rect = q2d.Rectangle{0, 0, pad.Left + tt.width, 200}
+		pos := q2d.Point{pad.Left + tt.col*7 + 3, pad.Top + tt.row*lh + 2}
+		ta.Event(MouseEvent{TypeVal: EventMouseDown, Pos: pos, Clicks: tt.clicks})
+		if got := ta.SelectedText(); got != tt.want {
+			t.Errorf("%s: selected %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package qui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// selectorPart is one compound selector such as Button#ok.primary.
+type selectorPart struct {
+	typeName string // Empty matches any type
+	id       string
+	classes  []string
+}
+
+func (p *selectorPart) matches(w Widget) bool {
+	if p.typeName != "" && typeName(w) != p.typeName {
+		return false
+	}
+	if p.id != "" && idOf(w) != p.id {
+		return false
+	}
+	for _, c := range p.classes {
+		if !hasClass(w, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// typeName returns the name of the concrete type of w, e.g. "Button".
+func typeName(w Widget) string {
+	t := reflect.TypeOf(w)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Selector matches widgets by type name, ID and style class, CSS style.
+// "Button" matches all buttons, "#save" the widget with the ID save and
+// ".danger" widgets with the class danger. These can be combined, as in
+// "Button#save.danger", and separated by spaces to match descendants:
+// "Window#prefs .danger" matches widgets of class danger inside the window
+// with the ID prefs. "*" matches any widget.
+type Selector struct {
+	parts []selectorPart
+}
+
+// ParseSelector parses a selector, see Selector.
+func ParseSelector(s string) (*Selector, error) {
+	ret := &Selector{}
+	for _, text := range strings.Fields(s) {
+		p, err := parseSelectorPart(text)
+		if err != nil {
+			return nil, err
+		}
+		ret.parts = append(ret.parts, p)
+	}
+	if len(ret.parts) == 0 {
+		return nil, fmt.Errorf("qui: empty selector")
+	}
+	return ret, nil
+}
+
+func parseSelectorPart(text string) (selectorPart, error) {
+	var p selectorPart
+	rest := text
+	if rest == "*" {
+		return p, nil
+	}
+	if i := strings.IndexAny(rest, "#."); i != 0 {
+		if i < 0 {
+			i = len(rest)
+		}
+		p.typeName = rest[:i]
+		rest = rest[i:]
+	}
+	for rest != "" {
+		kind := rest[0]
+		rest = rest[1:]
+		i := strings.IndexAny(rest, "#.")
+		if i < 0 {
+			i = len(rest)
+		}
+		name := rest[:i]
+		rest = rest[i:]
+		if name == "" {
+			return p, fmt.Errorf("qui: missing name after %q in selector %q", kind, text)
+		}
+		if kind == '#' {
+			if p.id != "" {
+				return p, fmt.Errorf("qui: more than one ID in selector %q", text)
+			}
+			p.id = name
+		} else {
+			p.classes = append(p.classes, name)
+		}
+	}
+	return p, nil
+}
+
+// Matches returns true if w matches the selector.
+func (s *Selector) Matches(w Widget) bool {
+	last := len(s.parts) - 1
+	if !s.parts[last].matches(w) {
+		return false
+	}
+	// Match the remaining parts against the ancestors, nearest first
+	i := last - 1
+	for p := parentOf(w); p != nil && i >= 0; p = parentOf(p) {
+		if s.parts[i].matches(p) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+// FindAll returns the widgets in the tree rooted at root that match, in the
+// order of Walk.
+func (s *Selector) FindAll(root Widget) []Widget {
+	return FindWidgets(root, s.Matches)
+}
+
+// Query returns the widgets in the tree rooted at root that match selector.
+func Query(root Widget, selector string) ([]Widget, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return s.FindAll(root), nil
+}
+
+// FindByID returns the first widget in the tree rooted at root with the given
+// ID, or nil. Nothing is found for an empty id.
+func FindByID(root Widget, id string) Widget {
+	if id == "" {
+		return nil
+	}
+	return FindWidget(root, func(w Widget) bool {
+		return idOf(w) == id
+	})
+}
+
+// roots returns the root widget followed by the overlays, bottom to top.
+func (m *Master) roots() []Widget {
+	var ret []Widget
+	if m.Root != nil {
+		ret = append(ret, m.Root)
+	}
+	return append(ret, m.Overlays...)
+}
+
+// FindByID returns the first widget with the given ID in the root widget or
+// the overlays, or nil.
+func (m *Master) FindByID(id string) Widget {
+	if id == "" {
+		return nil
+	}
+	for _, r := range m.roots() {
+		if w := FindByID(r, id); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// Query returns the widgets in the root widget and the overlays that match
+// selector, see Selector.
+func (m *Master) Query(selector string) ([]Widget, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	var ret []Widget
+	for _, r := range m.roots() {
+		ret = append(ret, s.FindAll(r)...)
+	}
+	return ret, nil
+}
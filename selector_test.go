@@ -0,0 +1,100 @@
+package qui
+
+import "testing"
+
+func TestParseSelectorErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"#",
+		".",
+		"Button#",
+		"Button.",
+		"Button.primary.",
+		"#save#ok",
+		".a..b",
+		"Window #",
+		"Window#prefs .danger#",
+	}
+	for _, text := range tests {
+		if s, err := ParseSelector(text); err == nil {
+			t.Errorf("ParseSelector(%q) = %+v, want error", text, s.parts)
+		}
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	save := NewButton("Save", nil)
+	save.ID = "save"
+	save.Classes = []string{"primary"}
+	cancel := NewButton("Cancel", nil)
+	cancel.Classes = []string{"danger"}
+	label := NewLabel("Name")
+	label.Classes = []string{"danger", "small"}
+	form := NewContainer(LayoutVertical, label, save, cancel)
+	form.ID = "form"
+	root := NewContainer(LayoutVertical, form)
+
+	tests := []struct {
+		selector string
+		want     []Widget
+	}{
+		{"*", []Widget{root, form, label, save, cancel}},
+		{"Button", []Widget{save, cancel}},
+		{"#save", []Widget{save}},
+		{".danger", []Widget{label, cancel}},
+		{".danger.small", []Widget{label}},
+		{"Button.danger", []Widget{cancel}},
+		{"Button#save.primary", []Widget{save}},
+		{"Button#save.danger", nil},
+		{"#form Button", []Widget{save, cancel}},
+		{"Container Container .danger", []Widget{label, cancel}},
+		{"#form #form Button", nil},
+		{"Label Button", nil},
+		{"#missing", nil},
+	}
+	for _, tt := range tests {
+		got, err := Query(root, tt.selector)
+		if err != nil {
+			t.Errorf("Query(%q) error: %v", tt.selector, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("Query(%q) found %d widgets, want %d", tt.selector, len(got), len(tt.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Query(%q)[%d] = %T, want %T", tt.selector, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFindByID(t *testing.T) {
+	save := NewButton("Save", nil)
+	save.ID = "save"
+	root := NewContainer(LayoutVertical, NewLabel("No ID"), save)
+	popup := NewLabel("Popup")
+	popup.ID = "popup"
+	m := NewMaster(root, nil)
+	m.PushOverlay(popup)
+
+	tests := []struct {
+		id   string
+		want Widget
+	}{
+		{"save", save},
+		{"popup", popup},
+		{"", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := m.FindByID(tt.id); got != tt.want {
+			t.Errorf("Master.FindByID(%q) = %T, want %T", tt.id, got, tt.want)
+		}
+	}
+	if got := FindByID(root, ""); got != nil {
+		t.Errorf("FindByID(root, \"\") = %T, want nil", got)
+	}
+}
@@ -0,0 +1,290 @@
+package qui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShortcutTimeout is how long Master waits for the next stroke of a
+// multi-stroke shortcut before giving up on it.
+var ShortcutTimeout = 1500 * time.Millisecond
+
+// Chord is a single key press with modifiers. Letter keys use the codes of the
+// upper case letters 'A' to 'Z' and digit keys the codes '0' to '9'.
+type Chord struct {
+	Key  int
+	Mods KeyMod
+}
+
+var keyNames = map[int]string{
+	KeyBackspace: "Backspace",
+	KeyTab:       "Tab",
+	KeyEnter:     "Enter",
+	KeyEscape:    "Esc",
+	KeySpace:     "Space",
+	KeyPageUp:    "PageUp",
+	KeyPageDown:  "PageDown",
+	KeyEnd:       "End",
+	KeyHome:      "Home",
+	KeyLeft:      "Left",
+	KeyUp:        "Up",
+	KeyRight:     "Right",
+	KeyDown:      "Down",
+	KeyInsert:    "Insert",
+	KeyDelete:    "Delete",
+}
+
+func (c Chord) String() string {
+	var sb strings.Builder
+	if c.Mods&ModCtrl != 0 {
+		sb.WriteString("Ctrl+")
+	}
+	if c.Mods&ModAlt != 0 {
+		sb.WriteString("Alt+")
+	}
+	if c.Mods&ModShift != 0 {
+		sb.WriteString("Shift+")
+	}
+	if c.Mods&ModSuper != 0 {
+		sb.WriteString("Super+")
+	}
+	switch {
+	case keyNames[c.Key] != "":
+		sb.WriteString(keyNames[c.Key])
+	case c.Key >= KeyF1 && c.Key < KeyF1+12:
+		fmt.Fprintf(&sb, "F%d", c.Key-KeyF1+1)
+	case c.Key >= 'A' && c.Key <= 'Z', c.Key >= '0' && c.Key <= '9':
+		sb.WriteRune(rune(c.Key))
+	default:
+		fmt.Fprintf(&sb, "#%d", c.Key)
+	}
+	return sb.String()
+}
+
+// isModifierKey returns true for keys that only ever act as modifiers.
+func isModifierKey(key int) bool {
+	return key == KeyShift || key == KeyCtrl || key == KeyAlt || key == KeySuper
+}
+
+// Shortcut is a sequence of one or more chords pressed one after the other,
+// such as Ctrl+K Ctrl+C.
+type Shortcut []Chord
+
+func (s Shortcut) String() string {
+	parts := make([]string, len(s))
+	for i, c := range s {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// hasPrefix returns true if p is the start of s.
+func (s Shortcut) hasPrefix(p Shortcut) bool {
+	if len(p) > len(s) {
+		return false
+	}
+	for i := range p {
+		if s[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseShortcut parses a shortcut written as space-separated chords of
+// +-separated modifiers and a key, such as "Ctrl+S", "F5" or
+// "Ctrl+K Ctrl+C". Names are case-insensitive.
+func ParseShortcut(text string) (Shortcut, error) {
+	var ret Shortcut
+	for _, stroke := range strings.Fields(text) {
+		c, err := parseChord(stroke)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("qui: empty shortcut")
+	}
+	return ret, nil
+}
+
+func parseChord(stroke string) (Chord, error) {
+	var c Chord
+	parts := strings.Split(stroke, "+")
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.ToLower(mod) {
+		case "ctrl", "control":
+			c.Mods |= ModCtrl
+		case "shift":
+			c.Mods |= ModShift
+		case "alt":
+			c.Mods |= ModAlt
+		case "super", "cmd", "meta":
+			c.Mods |= ModSuper
+		default:
+			return c, fmt.Errorf("qui: unknown modifier %q in shortcut %q", mod, stroke)
+		}
+	}
+
+	name := strings.ToUpper(parts[len(parts)-1])
+	for k, n := range keyNames {
+		if strings.ToUpper(n) == name {
+			c.Key = k
+			return c, nil
+		}
+	}
+	if name == "ESCAPE" {
+		c.Key = KeyEscape
+		return c, nil
+	}
+	var f int
+	if _, err := fmt.Sscanf(name, "F%d", &f); err == nil && f >= 1 && f <= 12 && name == fmt.Sprintf("F%d", f) {
+		c.Key = KeyF1 + f - 1
+		return c, nil
+	}
+	if len(name) == 1 && (name[0] >= 'A' && name[0] <= 'Z' || name[0] >= '0' && name[0] <= '9') {
+		c.Key = int(name[0])
+		return c, nil
+	}
+	return c, fmt.Errorf("qui: unknown key %q in shortcut %q", parts[len(parts)-1], stroke)
+}
+
+// ShortcutBinding ties a shortcut to a handler. Bindings with a Scope only
+// fire while the focused widget is the scope or inside it, or while the scope
+// is the topmost overlay. Scoped bindings take precedence over global ones,
+// and narrower scopes over wider ones. This includes a binding in a narrower
+// scope that starts with a shortcut bound in a wider one: while the narrower
+// scope is active, Master waits for the rest of the longer shortcut.
+type ShortcutBinding struct {
+	Shortcut Shortcut
+	Scope    Widget // nil for global
+	Handler  func()
+	// Disabled bindings still reserve their shortcut but do nothing.
+	Disabled bool
+
+	item *MenuItem // Item showing the shortcut, if any
+}
+
+// BindShortcut registers handler to run when shortcut is pressed while scope
+// is active, see ShortcutBinding. An error is returned if the shortcut cannot
+// be parsed or conflicts with another binding. Bindings conflict if they are
+// in the same scope and are the same or one is the start of the other.
+// Bindings in a narrower scope shadow the wider ones, so the same shortcut,
+// its start or an extension of it in a narrower scope is not a conflict.
+func (m *Master) BindShortcut(shortcut string, scope Widget, handler func()) (*ShortcutBinding, error) {
+	s, err := ParseShortcut(shortcut)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range m.shortcuts {
+		if b.Scope != scope {
+			continue
+		}
+		if b.Shortcut.hasPrefix(s) || s.hasPrefix(b.Shortcut) {
+			return nil, fmt.Errorf("qui: shortcut %s conflicts with %s", s, b.Shortcut)
+		}
+	}
+	b := &ShortcutBinding{
+		Shortcut: s,
+		Scope:    scope,
+		Handler:  handler,
+	}
+	m.shortcuts = append(m.shortcuts, b)
+	return b, nil
+}
+
+// BindMenuItem binds shortcut to activate item and shows it on the item.
+func (m *Master) BindMenuItem(shortcut string, scope Widget, item *MenuItem) (*ShortcutBinding, error) {
+	b, err := m.BindShortcut(shortcut, scope, item.Activate)
+	if err != nil {
+		return nil, err
+	}
+	b.item = item
+	item.Shortcut = b.Shortcut.String()
+	return b, nil
+}
+
+// UnbindShortcut removes a binding returned by BindShortcut or BindMenuItem.
+func (m *Master) UnbindShortcut(b *ShortcutBinding) {
+	for i, o := range m.shortcuts {
+		if o == b {
+			m.shortcuts = append(m.shortcuts[:i], m.shortcuts[i+1:]...)
+			if b.item != nil && b.item.Shortcut == b.Shortcut.String() {
+				b.item.Shortcut = ""
+			}
+			m.pendingChords = nil
+			return
+		}
+	}
+}
+
+// scopeActive returns true if bindings scoped to w currently apply.
+func (m *Master) scopeActive(w Widget) bool {
+	if w == nil {
+		return true
+	}
+	if len(m.Overlays) > 0 && m.Overlays[len(m.Overlays)-1] == w {
+		return true
+	}
+	f, ok := m.FocusedWidget.(Widget)
+	return ok && IsDescendant(f, w)
+}
+
+// scopeDepth orders scopes from widest to narrowest, global first.
+func scopeDepth(w Widget) int {
+	if w == nil {
+		return -1
+	}
+	return len(Ancestors(w))
+}
+
+// handleShortcut feeds a key press to the shortcut registry and returns true
+// if it was consumed as part of a shortcut.
+func (m *Master) handleShortcut(e KeyEvent) bool {
+	if e.TypeVal != EventKeyDown || isModifierKey(e.Key) || len(m.shortcuts) == 0 {
+		return false
+	}
+	now := time.Now()
+	if len(m.pendingChords) > 0 && now.Sub(m.pendingTime) > ShortcutTimeout {
+		m.pendingChords = nil
+	}
+	pending := len(m.pendingChords) > 0
+	seq := append(m.pendingChords, Chord{e.Key, e.Mods})
+	m.pendingChords = nil
+
+	var match *ShortcutBinding
+	partial := false
+	partialDepth := -1
+	for _, b := range m.shortcuts {
+		if !b.Shortcut.hasPrefix(seq) || !m.scopeActive(b.Scope) {
+			continue
+		}
+		if len(b.Shortcut) > len(seq) {
+			partial = true
+			partialDepth = max(partialDepth, scopeDepth(b.Scope))
+			continue
+		}
+		if match == nil || scopeDepth(b.Scope) > scopeDepth(match.Scope) {
+			match = b
+		}
+	}
+	// A longer shortcut in a narrower scope shadows the match
+	if match != nil && partial && partialDepth > scopeDepth(match.Scope) {
+		match = nil
+	}
+	if match != nil {
+		if !match.Disabled && match.Handler != nil {
+			match.Handler()
+		}
+		return true
+	}
+	if partial {
+		m.pendingChords = seq
+		m.pendingTime = now
+		return true
+	}
+	// A key that breaks off a sequence is swallowed
+	return pending
+}
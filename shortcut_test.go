@@ -0,0 +1,168 @@
+package qui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseShortcut(t *testing.T) {
+	tests := []struct {
+		text string
+		want Shortcut
+	}{
+		{"A", Shortcut{{Key: 'A'}}},
+		{"ctrl+s", Shortcut{{Key: 'S', Mods: ModCtrl}}},
+		{"Control+Shift+Z", Shortcut{{Key: 'Z', Mods: ModCtrl | ModShift}}},
+		{"Alt+Super+1", Shortcut{{Key: '1', Mods: ModAlt | ModSuper}}},
+		{"Cmd+Meta+0", Shortcut{{Key: '0', Mods: ModSuper}}},
+		{"F1", Shortcut{{Key: KeyF1}}},
+		{"f12", Shortcut{{Key: KeyF1 + 11}}},
+		{"Esc", Shortcut{{Key: KeyEscape}}},
+		{"Escape", Shortcut{{Key: KeyEscape}}},
+		{"Enter", Shortcut{{Key: KeyEnter}}},
+		{"Shift+Tab", Shortcut{{Key: KeyTab, Mods: ModShift}}},
+		{"Space", Shortcut{{Key: KeySpace}}},
+		{"PageDown", Shortcut{{Key: KeyPageDown}}},
+		{"ctrl+delete", Shortcut{{Key: KeyDelete, Mods: ModCtrl}}},
+		{"Ctrl+K Ctrl+C", Shortcut{{Key: 'K', Mods: ModCtrl}, {Key: 'C', Mods: ModCtrl}}},
+		{"  Ctrl+K   G  ", Shortcut{{Key: 'K', Mods: ModCtrl}, {Key: 'G'}}},
+		{"G G G", Shortcut{{Key: 'G'}, {Key: 'G'}, {Key: 'G'}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseShortcut(tt.text)
+		if err != nil {
+			t.Errorf("ParseShortcut(%q) error: %v", tt.text, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseShortcut(%q) = %v, want %v", tt.text, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseShortcut(%q) = %v, want %v", tt.text, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseShortcutErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"Ctrl+",
+		"Hyper+A",
+		"Ctrl+Foo",
+		"F0",
+		"F13",
+		"F1x",
+		"AB",
+		"Ctrl+K Ctrl+",
+		"+",
+	}
+	for _, text := range tests {
+		if s, err := ParseShortcut(text); err == nil {
+			t.Errorf("ParseShortcut(%q) = %v, want error", text, s)
+		}
+	}
+}
+
+func TestShortcutString(t *testing.T) {
+	tests := []string{
+		"Ctrl+S",
+		"Ctrl+Alt+Shift+Super+F5",
+		"Ctrl+K Ctrl+C",
+		"Esc",
+		"Shift+PageUp",
+		"9",
+	}
+	for _, text := range tests {
+		s, err := ParseShortcut(text)
+		if err != nil {
+			t.Errorf("ParseShortcut(%q) error: %v", text, err)
+			continue
+		}
+		if got := s.String(); got != text {
+			t.Errorf("ParseShortcut(%q).String() = %q", text, got)
+		}
+	}
+}
+
+func TestBindShortcutConflicts(t *testing.T) {
+	inner := NewButton("Inner", nil)
+	other := NewButton("Other", nil)
+	editor := NewContainer(LayoutVertical, inner)
+	root := NewContainer(LayoutVertical, editor, other)
+
+	tests := []struct {
+		name     string
+		existing string
+		scope    Widget
+		shortcut string
+		with     Widget
+		conflict bool
+	}{
+		{"same global", "Ctrl+S", nil, "Ctrl+S", nil, true},
+		{"same scope", "Ctrl+S", editor, "Ctrl+S", editor, true},
+		{"override in narrower scope", "Ctrl+S", nil, "Ctrl+S", editor, false},
+		{"override in nested scope", "Ctrl+S", editor, "Ctrl+S", inner, false},
+		{"prefix in same scope", "Ctrl+K", editor, "Ctrl+K Ctrl+C", editor, true},
+		{"global prefix of scoped", "Ctrl+K", nil, "Ctrl+K Ctrl+C", editor, false},
+		{"scoped extended globally", "Ctrl+K Ctrl+C", editor, "Ctrl+K", nil, false},
+		{"prefix in nested scope", "Ctrl+K", editor, "Ctrl+K Ctrl+C", inner, false},
+		{"scoped prefix of global", "Ctrl+K Ctrl+C", nil, "Ctrl+K", editor, false},
+		{"prefix in sibling scope", "Ctrl+K", other, "Ctrl+K Ctrl+C", editor, false},
+		{"unrelated", "Ctrl+K Ctrl+C", nil, "Ctrl+K Ctrl+U", nil, false},
+	}
+	for _, tt := range tests {
+		m := NewMaster(root, nil)
+		if _, err := m.BindShortcut(tt.existing, tt.scope, nil); err != nil {
+			t.Fatalf("%s: binding %q: %v", tt.name, tt.existing, err)
+		}
+		_, err := m.BindShortcut(tt.shortcut, tt.with, nil)
+		if got := err != nil; got != tt.conflict {
+			t.Errorf("%s: binding %q after %q: error %v, want conflict %v", tt.name, tt.shortcut, tt.existing, err, tt.conflict)
+		}
+	}
+}
+
+func TestShortcutShadowing(t *testing.T) {
+	ctrl := func(key int) KeyEvent { return KeyEvent{TypeVal: EventKeyDown, Key: key, Mods: ModCtrl} }
+	tests := []struct {
+		name  string
+		focus string
+		keys  []KeyEvent
+		want  []string
+	}{
+		{"global outside the scope", "other", []KeyEvent{ctrl('K')}, []string{"global Ctrl+K"}},
+		{"scoped extension waits", "inner", []KeyEvent{ctrl('K')}, nil},
+		{"scoped extension", "inner", []KeyEvent{ctrl('K'), ctrl('C')}, []string{"editor Ctrl+K Ctrl+C"}},
+		{"scoped extension broken off", "inner", []KeyEvent{ctrl('K'), {TypeVal: EventKeyDown, Key: KeyEscape}}, nil},
+		{"scoped prefix", "inner", []KeyEvent{ctrl('U'), ctrl('X')}, []string{"editor Ctrl+U"}},
+		{"global extension outside the scope", "other", []KeyEvent{ctrl('U'), ctrl('X')}, []string{"global Ctrl+U Ctrl+X"}},
+	}
+	for _, tt := range tests {
+		widgets := map[string]*Button{"inner": NewButton("Inner", nil), "other": NewButton("Other", nil)}
+		editor := NewContainer(LayoutVertical, widgets["inner"])
+		root := NewContainer(LayoutVertical, editor, widgets["other"])
+		m := NewMaster(root, nil)
+		var fired []string
+		bind := func(shortcut string, scope Widget, name string) {
+			if _, err := m.BindShortcut(shortcut, scope, func() { fired = append(fired, name+" "+shortcut) }); err != nil {
+				t.Fatalf("%s: binding %q: %v", tt.name, shortcut, err)
+			}
+		}
+		bind("Ctrl+K", nil, "global")
+		bind("Ctrl+K Ctrl+C", editor, "editor")
+		bind("Ctrl+U Ctrl+X", nil, "global")
+		bind("Ctrl+U", editor, "editor")
+		m.SetFocus(widgets[tt.focus])
+		for _, k := range tt.keys {
+			m.Event(k)
+		}
+		if !slices.Equal(fired, tt.want) {
+			t.Errorf("%s: fired %v, want %v", tt.name, fired, tt.want)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package qui
+
+import (
+	"github.com/qbradq/q2d"
+)
+
+// SplitPane is one of the panes of a Splitter.
+type SplitPane struct {
+	Content Widget
+	// Size is the pane's share of the space left over by fixed panes, relative
+	// to the other flexible panes. If Fixed is set Size is a width or height in
+	// pixels that is kept when the splitter is resized.
+	Size      float64
+	Fixed     bool
+	Collapsed bool
+
+	length int // Laid out size along the split axis
+}
+
+// hidden returns true if the pane takes no space.
+func (p *SplitPane) hidden() bool {
+	return p.Collapsed || p.absent()
+}
+
+// absent returns true if the pane's content is hidden. Unlike collapsed panes,
+// absent panes have no divider either.
+func (p *SplitPane) absent() bool {
+	return p.Content != nil && isHidden(p.Content)
+}
+
+// Splitter arranges any number of panes side by side (LayoutHorizontal) or
+// stacked (LayoutVertical) with draggable dividers between them. Panes never
+// shrink below their MinSize while dragging, and double-clicking a divider
+// collapses or restores the smaller adjacent pane.
+type Splitter struct {
+	BaseWidget
+	Direction LayoutDirection
+	Panes     []*SplitPane
+
+	hoveredDivider int
+	dragDivider    int
+	dragStart      q2d.Point
+	dragLengths    [2]int
+}
+
+// NewSplitter creates a splitter whose panes share the space equally.
+func NewSplitter(dir LayoutDirection, contents ...Widget) *Splitter {
+	s := &Splitter{
+		Direction:      dir,
+		hoveredDivider: -1,
+		dragDivider:    -1,
+	}
+	s.Fill = true
+	// Moves are seen on their way down to the panes too, so that a divider
+	// is no longer hovered once the pointer moves onto a pane
+	s.Handlers().OnCapture(EventMouseMove, func(ctx *EventContext) {
+		s.hoveredDivider = s.dividerAt(ctx.Event.(MouseEvent).Pos)
+	})
+	for _, c := range contents {
+		s.AddPane(c, 1, false)
+	}
+	return s
+}
+
+// AddPane appends a pane. See SplitPane for the meaning of size and fixed.
+func (s *Splitter) AddPane(content Widget, size float64, fixed bool) *SplitPane {
+	p := &SplitPane{
+		Content: content,
+		Size:    size,
+		Fixed:   fixed,
+	}
+	adopt(s, content)
+	s.Panes = append(s.Panes, p)
+	return p
+}
+
+// SetCollapsed collapses or restores the pane at index i.
+func (s *Splitter) SetCollapsed(i int, collapsed bool) {
+	if i < 0 || i >= len(s.Panes) {
+		return
+	}
+	s.Panes[i].Collapsed = collapsed
+	s.Layout(Size{s.Rect.Width(), s.Rect.Height()})
+}
+
+func (s *Splitter) dividerSize() int {
+	t := s.GetTheme()
+	if t != nil && t.Spacing >= 2 {
+		return t.Spacing
+	}
+	return t.Px(4)
+}
+
+// next returns the index of the first pane after pane i that is not absent,
+// or -1.
+func (s *Splitter) next(i int) int {
+	for j := i + 1; j < len(s.Panes); j++ {
+		if !s.Panes[j].absent() {
+			return j
+		}
+	}
+	return -1
+}
+
+// hasDivider returns true if pane i is followed by a divider, which joins it
+// to the next pane that is not absent.
+func (s *Splitter) hasDivider(i int) bool {
+	return !s.Panes[i].absent() && s.next(i) >= 0
+}
+
+// dividers returns the number of dividers.
+func (s *Splitter) dividers() int {
+	n := 0
+	for i := range s.Panes {
+		if s.hasDivider(i) {
+			n++
+		}
+	}
+	return n
+}
+
+// axis returns the component of sz along the split axis.
+func (s *Splitter) axis(sz Size) int {
+	if s.Direction == LayoutHorizontal {
+		return sz.Width
+	}
+	return sz.Height
+}
+
+func (s *Splitter) paneMin(p *SplitPane) int {
+	if p.Content == nil {
+		return 0
+	}
+	return s.axis(p.Content.MinSize())
+}
+
+// computeLengths distributes the splitter's length among the panes.
+func (s *Splitter) computeLengths() {
+	if len(s.Panes) == 0 {
+		return
+	}
+	space := s.axis(Size{s.Rect.Width(), s.Rect.Height()}) - s.dividerSize()*s.dividers()
+
+	// Collapsed and fixed panes first
+	remaining := space
+	weight := 0.0
+	var flexible []*SplitPane
+	for _, p := range s.Panes {
+		switch {
+		case p.hidden():
+			p.length = 0
+		case p.Fixed:
+			p.length = max(int(p.Size), s.paneMin(p))
+			remaining -= p.length
+		default:
+			flexible = append(flexible, p)
+			weight += p.Size
+		}
+	}
+	if len(flexible) == 0 {
+		return
+	}
+
+	// Share out the rest, the last flexible pane takes the rounding error
+	left := max(remaining, 0)
+	for i, p := range flexible {
+		if i == len(flexible)-1 {
+			p.length = left
+			break
+		}
+		share := 1.0 / float64(len(flexible))
+		if weight > 0 {
+			share = p.Size / weight
+		}
+		p.length = int(float64(max(remaining, 0)) * share)
+		left -= p.length
+	}
+
+	// Grow panes below their minimum at the expense of the others
+	deficit := 0
+	for _, p := range flexible {
+		if m := s.paneMin(p); p.length < m {
+			deficit += m - p.length
+			p.length = m
+		}
+	}
+	for _, p := range flexible {
+		if deficit == 0 {
+			break
+		}
+		if slack := p.length - s.paneMin(p); slack > 0 {
+			take := min(slack, deficit)
+			p.length -= take
+			deficit -= take
+		}
+	}
+}
+
+// paneRect returns the rectangle of the pane starting offset pixels into the
+// splitter.
+func (s *Splitter) paneRect(offset, length int) q2d.Rectangle {
+	if s.Direction == LayoutHorizontal {
+		return q2d.Rectangle{s.Rect.X() + offset, s.Rect.Y(), length, s.Rect.Height()}
+	}
+	return q2d.Rectangle{s.Rect.X(), s.Rect.Y() + offset, s.Rect.Width(), length}
+}
+
+// dividerRect returns the rectangle of the divider following pane i.
+func (s *Splitter) dividerRect(i int) q2d.Rectangle {
+	offset := 0
+	for j := 0; j <= i; j++ {
+		offset += s.Panes[j].length
+		if j < i && s.hasDivider(j) {
+			offset += s.dividerSize()
+		}
+	}
+	return s.paneRect(offset, s.dividerSize())
+}
+
+// dividerAt returns the index of the divider at pos, or -1.
+func (s *Splitter) dividerAt(pos q2d.Point) int {
+	for i := range s.Panes {
+		if s.hasDivider(i) && s.dividerRect(i).Contains(pos) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Splitter) MinSize() Size {
+	along, across := s.dividerSize()*s.dividers(), 0
+	for _, p := range s.Panes {
+		if p.hidden() || p.Content == nil {
+			continue
+		}
+		sz := p.Content.MinSize()
+		if s.Direction == LayoutHorizontal {
+			along += sz.Width
+			across = max(across, sz.Height)
+		} else {
+			along += sz.Height
+			across = max(across, sz.Width)
+		}
+	}
+	if s.Direction == LayoutHorizontal {
+		return Size{along, across}
+	}
+	return Size{across, along}
+}
+
+func (s *Splitter) GetChildren() []Widget {
+	var ret []Widget
+	for _, p := range s.Panes {
+		if p.Content != nil {
+			ret = append(ret, p.Content)
+		}
+	}
+	return ret
+}
+
+func (s *Splitter) Layout(available Size) Size {
+	for _, p := range s.Panes {
+		adopt(s, p.Content)
+	}
+	s.computeLengths()
+	s.layoutPanes()
+	return available
+}
+
+// layoutPanes positions the panes according to their computed lengths.
+func (s *Splitter) layoutPanes() {
+	offset := 0
+	for i, p := range s.Panes {
+		r := s.paneRect(offset, p.length)
+		if p.Content != nil && !p.hidden() {
+			p.Content.SetRect(r)
+			p.Content.Layout(Size{r.Width(), r.Height()})
+		}
+		offset += p.length
+		if s.hasDivider(i) {
+			offset += s.dividerSize()
+		}
+	}
+}
+
+// toggleCollapse handles a double-click on divider i.
+func (s *Splitter) toggleCollapse(i int) {
+	a, b := s.Panes[i], s.Panes[s.next(i)]
+	switch {
+	case a.Collapsed:
+		a.Collapsed = false
+	case b.Collapsed:
+		b.Collapsed = false
+	case a.Fixed != b.Fixed:
+		// The fixed pane is usually the side panel
+		if a.Fixed {
+			a.Collapsed = true
+		} else {
+			b.Collapsed = true
+		}
+	case a.length <= b.length:
+		a.Collapsed = true
+	default:
+		b.Collapsed = true
+	}
+	s.Layout(Size{s.Rect.Width(), s.Rect.Height()})
+}
+
+// drag moves the divider being dragged by delta pixels from where the drag
+// started.
+func (s *Splitter) drag(delta int) {
+	a, b := s.Panes[s.dragDivider], s.Panes[s.next(s.dragDivider)]
+	total := s.dragLengths[0] + s.dragLengths[1]
+	lo, hi := s.paneMin(a), total-s.paneMin(b)
+	if lo > hi {
+		// Too little room for both minimums, keep the lengths
+		lo, hi = s.dragLengths[0], s.dragLengths[0]
+	}
+	a.length = min(max(s.dragLengths[0]+delta, lo), hi)
+	b.length = total - a.length
+	// Dragging a collapsed pane opens it again
+	a.Collapsed = a.Collapsed && a.length == 0
+	b.Collapsed = b.Collapsed && b.length == 0
+
+	// Store the new lengths back into the pane sizes
+	flexTotal := 0
+	for _, p := range s.Panes {
+		if !p.Fixed && !p.hidden() {
+			flexTotal += p.length
+		}
+	}
+	for _, p := range s.Panes {
+		if p.hidden() {
+			continue
+		}
+		if p.Fixed {
+			p.Size = float64(p.length)
+		} else if flexTotal > 0 {
+			p.Size = float64(p.length) / float64(flexTotal)
+		}
+	}
+	s.layoutPanes()
+}
+
+func (s *Splitter) Event(e Event) bool {
+	if s.Disabled {
+		return false
+	}
+	switch evt := e.(type) {
+	case MouseEvent:
+		if s.dragDivider >= 0 {
+			if evt.TypeVal == EventMouseUp {
+				s.dragDivider = -1
+				return true
+			}
+			if evt.TypeVal == EventMouseMove {
+				delta := evt.Pos.Sub(s.dragStart)
+				if s.Direction == LayoutHorizontal {
+					s.drag(delta.X())
+				} else {
+					s.drag(delta.Y())
+				}
+				return true
+			}
+		}
+
+		divider := -1
+		if s.Rect.Contains(evt.Pos) {
+			divider = s.dividerAt(evt.Pos)
+		}
+		if evt.TypeVal == EventMouseLeave {
+			s.hoveredDivider = -1
+			return false
+		}
+		if evt.TypeVal == EventMouseDown && divider >= 0 {
+			if evt.Clicks == 2 {
+				s.toggleCollapse(divider)
+				return true
+			}
+			s.dragDivider = divider
+			s.dragStart = evt.Pos
+			s.dragLengths = [2]int{s.Panes[divider].length, s.Panes[s.next(divider)].length}
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Splitter) GrabsPointer() bool {
+	return s.dragDivider >= 0
+}
+
+func (s *Splitter) FindWidgetAt(pos q2d.Point) Widget {
+	if !s.Rect.Contains(pos) {
+		return nil
+	}
+	for _, p := range s.Panes {
+		if p.Content == nil || p.hidden() {
+			continue
+		}
+		if w := p.Content.FindWidgetAt(pos); w != nil {
+			return w
+		}
+	}
+	return s
+}
+
+func (s *Splitter) Draw(img *q2d.Image) {
+	theme := s.GetTheme()
+	if theme == nil {
+		return
+	}
+
+	for _, p := range s.Panes {
+		if p.Content != nil && !p.hidden() {
+			p.Content.Draw(img)
+		}
+	}
+
+	for i := range s.Panes {
+		if !s.hasDivider(i) {
+			continue
+		}
+		state := s.state()
+		if i == s.dragDivider {
+			state |= StatePressed
+		} else if i == s.hoveredDivider {
+			state |= StateHovered
+		}
+		DrawBox(img, s.dividerRect(i), theme.Style(StyleDivider, state))
+	}
+}
@@ -0,0 +1,180 @@
+package qui
+
+import (
+	"testing"
+
+	"github.com/qbradq/q2d"
+)
+
+// minWidget is a widget with a fixed minimum size.
+type minWidget struct {
+	BaseWidget
+	min Size
+}
+
+func (w *minWidget) MinSize() Size {
+	return w.min
+}
+
+// testPane describes a pane of a splitter built by newTestSplitter.
+type testPane struct {
+	size      float64
+	fixed     bool
+	collapsed bool
+	hidden    bool
+	min       int
+}
+
+// newTestSplitter returns a horizontal splitter with space pixels to share
+// among the panes besides the dividers.
+func newTestSplitter(space int, panes ...testPane) *Splitter {
+	s := NewSplitter(LayoutHorizontal)
+	for _, tp := range panes {
+		w := &minWidget{min: Size{tp.min, 10}}
+		w.Hidden = tp.hidden
+		s.AddPane(w, tp.size, tp.fixed).Collapsed = tp.collapsed
+	}
+	width := space + s.dividerSize()*s.dividers()
+	s.SetRect(q2d.Rectangle{0, 0, width, 100})
+	s.Layout(Size{width, 100})
+	return s
+}
+
+func paneLengths(s *Splitter) []int {
+	ret := make([]int, len(s.Panes))
+	for i, p := range s.Panes {
+		ret[i] = p.length
+	}
+	return ret
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSplitterLengths(t *testing.T) {
+	tests := []struct {
+		name  string
+		space int
+		panes []testPane
+		want  []int
+	}{
+		{"equal", 300, []testPane{{size: 1}, {size: 1}, {size: 1}}, []int{100, 100, 100}},
+		{"weighted", 300, []testPane{{size: 1}, {size: 2}}, []int{100, 200}},
+		{"zero weights share equally", 300, []testPane{{}, {}}, []int{150, 150}},
+		{"rounding goes to the last pane", 301, []testPane{{size: 1}, {size: 1}, {size: 1}}, []int{100, 100, 101}},
+		{"fixed first", 300, []testPane{{size: 50, fixed: true}, {size: 1}, {size: 1}}, []int{50, 125, 125}},
+		{"fixed below min", 300, []testPane{{size: 20, fixed: true, min: 40}, {size: 1}}, []int{40, 260}},
+		{"flexible below min", 300, []testPane{{size: 1, min: 200}, {size: 1}}, []int{200, 100}},
+		{"min taken from earlier panes first", 300, []testPane{{size: 1, min: 20}, {size: 1, min: 20}, {size: 1, min: 250}}, []int{20, 30, 250}},
+		{"mins exceed space", 300, []testPane{{size: 1, min: 200}, {size: 1, min: 200}}, []int{200, 200}},
+		{"collapsed middle", 300, []testPane{{size: 1}, {size: 1, collapsed: true}, {size: 1}}, []int{150, 0, 150}},
+		{"collapsed fixed", 300, []testPane{{size: 80, fixed: true, collapsed: true}, {size: 1}}, []int{0, 300}},
+		{"collapsed ignores min", 300, []testPane{{size: 1, min: 500, collapsed: true}, {size: 1}}, []int{0, 300}},
+		{"hidden content", 300, []testPane{{size: 1}, {size: 1, hidden: true}}, []int{300, 0}},
+		{"hidden middle", 300, []testPane{{size: 1}, {size: 1, hidden: true}, {size: 1}}, []int{150, 0, 150}},
+		{"fixed overflows", 100, []testPane{{size: 150, fixed: true}, {size: 1}}, []int{150, 0}},
+	}
+	for _, tt := range tests {
+		s := newTestSplitter(tt.space, tt.panes...)
+		if got := paneLengths(s); !equalInts(got, tt.want) {
+			t.Errorf("%s: lengths %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitterToggleCollapse(t *testing.T) {
+	tests := []struct {
+		name      string
+		panes     []testPane
+		collapsed int
+	}{
+		{"smaller first", []testPane{{size: 1}, {size: 2}}, 0},
+		{"smaller second", []testPane{{size: 2}, {size: 1}}, 1},
+		{"equal collapses first", []testPane{{size: 1}, {size: 1}}, 0},
+		{"fixed side panel", []testPane{{size: 1}, {size: 250, fixed: true}}, 1},
+		{"fixed over smaller", []testPane{{size: 250, fixed: true}, {size: 1}}, 0},
+	}
+	for _, tt := range tests {
+		s := newTestSplitter(300, tt.panes...)
+		before := paneLengths(s)
+		s.toggleCollapse(0)
+		for i, p := range s.Panes {
+			if p.Collapsed != (i == tt.collapsed) {
+				t.Errorf("%s: pane %d collapsed %v", tt.name, i, p.Collapsed)
+			}
+		}
+		if got := paneLengths(s); got[tt.collapsed] != 0 || got[1-tt.collapsed] != 300 {
+			t.Errorf("%s: lengths %v after collapsing pane %d", tt.name, got, tt.collapsed)
+		}
+		s.toggleCollapse(0)
+		if got := paneLengths(s); !equalInts(got, before) {
+			t.Errorf("%s: lengths %v after restoring, want %v", tt.name, got, before)
+		}
+	}
+}
+
+func TestSplitterDrag(t *testing.T) {
+	tests := []struct {
+		name  string
+		panes []testPane
+		delta int
+		want  []int
+	}{
+		{"right", []testPane{{size: 1}, {size: 1}}, 30, []int{180, 120}},
+		{"left", []testPane{{size: 1}, {size: 1}}, -30, []int{120, 180}},
+		{"clamped by first min", []testPane{{size: 1, min: 100}, {size: 1}}, -200, []int{100, 200}},
+		{"clamped by second min", []testPane{{size: 1}, {size: 1, min: 50}}, 200, []int{250, 50}},
+		{"opens collapsed pane", []testPane{{size: 1, collapsed: true}, {size: 1}}, 40, []int{40, 260}},
+		{"opens collapsed pane to its min", []testPane{{size: 1, collapsed: true, min: 60}, {size: 1}}, 40, []int{60, 240}},
+		{"space below the mins", []testPane{{size: 1, collapsed: true, min: 200}, {size: 1, min: 200}}, 40, []int{0, 300}},
+		{"space below the mins backwards", []testPane{{size: 1, collapsed: true, min: 200}, {size: 1, min: 200}}, -40, []int{0, 300}},
+		{"across a hidden pane", []testPane{{size: 1}, {size: 1, hidden: true}, {size: 1}}, 30, []int{180, 0, 120}},
+	}
+	for _, tt := range tests {
+		s := newTestSplitter(300, tt.panes...)
+		s.dragDivider = 0
+		s.dragLengths = [2]int{s.Panes[0].length, s.Panes[s.next(0)].length}
+		s.drag(tt.delta)
+		if got := paneLengths(s); !equalInts(got, tt.want) {
+			t.Errorf("%s: lengths %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		// The dragged lengths are kept when laid out again
+		s.Layout(Size{s.Rect.Width(), s.Rect.Height()})
+		if got := paneLengths(s); !equalInts(got, tt.want) {
+			t.Errorf("%s: lengths %v after Layout, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitterHiddenPane(t *testing.T) {
+	s := newTestSplitter(300, testPane{size: 1, min: 10}, testPane{size: 1, min: 10, hidden: true}, testPane{size: 1, min: 10})
+	d := s.dividerSize()
+	if got := s.dividers(); got != 1 {
+		t.Errorf("dividers() = %d, want 1", got)
+	}
+	if got, want := s.MinSize().Width, 20+d; got != want {
+		t.Errorf("MinSize().Width = %d, want %d", got, want)
+	}
+	if got, want := s.dividerRect(0), (q2d.Rectangle{150, 0, d, 100}); got != want {
+		t.Errorf("dividerRect(0) = %v, want %v", got, want)
+	}
+	if got, want := s.Panes[2].Content.GetRect().X(), 150+d; got != want {
+		t.Errorf("last pane at x %d, want %d", got, want)
+	}
+	for x := 0; x < 300+d; x++ {
+		i := s.dividerAt(q2d.Point{x, 50})
+		if want := x >= 150 && x < 150+d; (i == 0) != want || i > 0 {
+			t.Errorf("dividerAt x %d = %d", x, i)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package qui
+
+import (
+	"reflect"
+
+	"github.com/qbradq/q2d"
+)
+
+// Style is how a kind of widget looks in a state. Fields left at their zero
+// value are not set, so that a style only needs the values it changes.
+type Style struct {
+	Background q2d.Color
+	// Gradient makes the background a vertical linear gradient from
+	// Background at the top to Gradient at the bottom.
+	Gradient q2d.Color
+	Border   q2d.Color
+	Text     q2d.Color
+	// BorderWidth is the width of the border in pixels. A negative width
+	// draws no border.
+	BorderWidth int
+	// Radius rounds the corners of the background and border.
+	Radius  int
+	Padding Padding
+}
+
+// merge returns s with the fields of o that are set copied over it.
+func (s Style) merge(o Style) Style {
+	mergeValue(reflect.ValueOf(&s).Elem(), reflect.ValueOf(o))
+	return s
+}
+
+// StyleState is a set of states a widget is in.
+type StyleState int
+
+const (
+	StateHovered StyleState = 1 << iota
+	StatePressed
+	StateFocused
+	StateDisabled
+	// StateSelected is the state of selected list items, checked buttons and
+	// the active tab.
+	StateSelected
+)
+
+// Each state with a Style of its own in StateStyles
+var styleStates = []StyleState{0, StateHovered, StatePressed, StateFocused, StateDisabled, StateSelected}
+
+// StateStyles are the styles of a kind of widget. Normal applies in every
+// state, and the styles of the states the widget is in are merged over it in
+// the order focused, hovered, selected, pressed and disabled.
+type StateStyles struct {
+	Normal   Style
+	Hovered  Style
+	Pressed  Style
+	Focused  Style
+	Disabled Style
+	Selected Style
+}
+
+// Get returns the style for state.
+func (s StateStyles) Get(state StyleState) Style {
+	st := s.Normal
+	for _, o := range []struct {
+		state StyleState
+		style Style
+	}{
+		{StateFocused, s.Focused},
+		{StateHovered, s.Hovered},
+		{StateSelected, s.Selected},
+		{StatePressed, s.Pressed},
+		{StateDisabled, s.Disabled},
+	} {
+		if state&o.state != 0 {
+			st = st.merge(o.style)
+		}
+	}
+	return st
+}
+
+// StyleKind names the block of Styles used by a kind of widget.
+type StyleKind int
+
+const (
+	// StyleButton is used by Button.
+	StyleButton StyleKind = iota
+	// StyleInput is used by Entry and TextArea, selected for the highlight
+	// behind selected text.
+	StyleInput
+	// StyleList is used by the box of List and the drop down of Select.
+	StyleList
+	// StyleListItem is used by the items of List and Select.
+	StyleListItem
+	// StyleCheck is used by Checkbox and RadioButton.
+	StyleCheck
+	// StyleSelect is used by the closed Select.
+	StyleSelect
+	// StyleTab is used by the tabs of TabContainer.
+	StyleTab
+	// StyleMenuItem is used by MenuItem.
+	StyleMenuItem
+	// StyleWindow is used by the body and frame of Window. The border width
+	// is the width of the frame.
+	StyleWindow
+	// StyleHeader is used by the title bar of Window.
+	StyleHeader
+	// StyleMenu is used by PopupMenu.
+	StyleMenu
+	// StyleMenuBar is used by MenuBar.
+	StyleMenuBar
+	// StyleTabBar is used by the strip behind the tabs of TabContainer.
+	StyleTabBar
+	// StylePanel is used by Panel.
+	StylePanel
+	// StyleInset is used by the area behind the pages of TabContainer and the
+	// panels of DockArea.
+	StyleInset
+	// StyleTooltip is used by tooltips.
+	StyleTooltip
+	// StyleScrollBar is used by the tracks of scroll bars and the corner
+	// between two of them.
+	StyleScrollBar
+	// StyleScrollThumb is used by the thumbs of scroll bars, pressed while
+	// dragged.
+	StyleScrollThumb
+	// StyleDivider is used by the dividers of Splitter, hovered and pressed
+	// while dragged, and selected for the insertion marks shown when dragging
+	// items over a List or tabs over a TabContainer.
+	StyleDivider
+	// StyleDrag is used by the box following the pointer during drag and drop.
+	StyleDrag
+	// StyleDropTarget is used by the drop indicators of DockArea, selected
+	// for the one under the pointer, and hovered for the preview of where the
+	// panel will go.
+	StyleDropTarget
+)
+
+// Styles are the per state styles of each kind of widget. They are derived
+// from the colors, Padding and Spacing of the theme, so only the values that
+// differ need to be set.
+type Styles struct {
+	Button   StateStyles
+	Input    StateStyles
+	List     StateStyles
+	ListItem StateStyles
+	Check    StateStyles
+	Select   StateStyles
+	Tab      StateStyles
+	MenuItem StateStyles
+	Window   StateStyles
+	Header   StateStyles
+	Menu     StateStyles
+	MenuBar  StateStyles
+	TabBar   StateStyles
+
+	Panel       StateStyles
+	Inset       StateStyles
+	Tooltip     StateStyles
+	ScrollBar   StateStyles
+	ScrollThumb StateStyles
+	Divider     StateStyles
+	Drag        StateStyles
+	DropTarget  StateStyles
+}
+
+func (s *Styles) kind(k StyleKind) *StateStyles {
+	switch k {
+	case StyleButton:
+		return &s.Button
+	case StyleInput:
+		return &s.Input
+	case StyleList:
+		return &s.List
+	case StyleListItem:
+		return &s.ListItem
+	case StyleCheck:
+		return &s.Check
+	case StyleSelect:
+		return &s.Select
+	case StyleTab:
+		return &s.Tab
+	case StyleWindow:
+		return &s.Window
+	case StyleHeader:
+		return &s.Header
+	case StyleMenu:
+		return &s.Menu
+	case StyleMenuBar:
+		return &s.MenuBar
+	case StyleTabBar:
+		return &s.TabBar
+	case StylePanel:
+		return &s.Panel
+	case StyleInset:
+		return &s.Inset
+	case StyleTooltip:
+		return &s.Tooltip
+	case StyleScrollBar:
+		return &s.ScrollBar
+	case StyleScrollThumb:
+		return &s.ScrollThumb
+	case StyleDivider:
+		return &s.Divider
+	case StyleDrag:
+		return &s.Drag
+	case StyleDropTarget:
+		return &s.DropTarget
+	default:
+		return &s.MenuItem
+	}
+}
+
+// Style returns the style of a kind of widget in state.
+func (t *Theme) Style(k StyleKind, state StyleState) Style {
+	def := t.defaultStyles(k)
+	mergeValue(reflect.ValueOf(&def).Elem(), reflect.ValueOf(*t.Styles.kind(k)))
+	return def.Get(state)
+}
+
+// defaultStyles returns the styles of a kind of widget derived from the
+// colors, Radius and BorderWidth of the theme.
+func (t *Theme) defaultStyles(k StyleKind) StateStyles {
+	disabled := Style{Background: t.DisabledColor, Text: t.DisabledTextColor}
+	bw := 1
+	if t.BorderWidth != 0 {
+		bw = t.BorderWidth
+	}
+	switch k {
+	case StyleButton:
+		pressed := Style{Background: t.ButtonColor.Darken(0.2)}
+		return StateStyles{
+			Normal: Style{
+				Background:  t.ButtonColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding: Padding{
+					Top:    t.Padding.Top * 2,
+					Right:  t.Padding.Right * 2,
+					Bottom: t.Padding.Bottom * 2,
+					Left:   t.Padding.Left * 2,
+				},
+			},
+			Hovered:  Style{Background: t.ButtonHoverColor},
+			Pressed:  pressed,
+			Focused:  Style{Border: t.PrimaryColor},
+			Disabled: disabled,
+			Selected: pressed,
+		}
+	case StyleInput:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor.Darken(0.1),
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding:     t.Padding,
+			},
+			Focused:  Style{Background: t.BackgroundColor.Lighten(0.1)},
+			Selected: Style{Background: t.PrimaryColor},
+			Disabled: disabled,
+		}
+	case StyleList:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+			},
+			Focused:  Style{Border: t.PrimaryColor},
+			Disabled: Style{Text: t.DisabledTextColor},
+		}
+	case StyleListItem:
+		return StateStyles{
+			Normal: Style{
+				Text:    t.TextColor,
+				Radius:  t.Radius,
+				Padding: Padding{Left: t.Padding.Left, Right: t.Padding.Right},
+			},
+			Hovered:  Style{Background: t.SecondaryColor},
+			Selected: Style{Background: t.PrimaryColor},
+			Disabled: Style{Text: t.DisabledTextColor},
+		}
+	case StyleCheck:
+		return StateStyles{
+			Normal:   Style{Text: t.TextColor, Radius: t.Radius, Padding: t.Padding},
+			Focused:  Style{Background: t.BackgroundColor.Lighten(0.1)},
+			Disabled: Style{Text: t.DisabledTextColor},
+		}
+	case StyleSelect:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.ButtonColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding:     t.Padding,
+			},
+			Disabled: disabled,
+		}
+	case StyleTab:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.ButtonColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding:     t.Padding,
+			},
+			Selected: Style{Background: t.BackgroundColor},
+			Disabled: Style{Text: t.DisabledTextColor},
+		}
+	case StyleWindow:
+		frame := t.Px(windowFrameWidth)
+		if t.BorderWidth != 0 {
+			frame = t.BorderWidth
+		}
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: frame,
+				Radius:      t.Radius,
+			},
+		}
+	case StyleHeader:
+		return StateStyles{
+			Normal: Style{
+				Background: t.PrimaryColor,
+				Text:       t.TextColor,
+				Radius:     t.Radius,
+				Padding:    t.Padding,
+			},
+		}
+	case StyleMenu:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor.Darken(0.1),
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+			},
+		}
+	case StyleMenuBar:
+		return StateStyles{
+			Normal: Style{Background: t.BackgroundColor.Darken(0.1), Text: t.TextColor},
+		}
+	case StyleTabBar, StyleInset:
+		return StateStyles{
+			Normal: Style{Background: t.BackgroundColor.Darken(0.2), Text: t.TextColor},
+		}
+	case StylePanel:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+			},
+		}
+	case StyleTooltip:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.BackgroundColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding:     t.Padding,
+			},
+		}
+	case StyleScrollBar:
+		return StateStyles{
+			Normal: Style{Background: t.BackgroundColor.Lighten(0.1)},
+		}
+	case StyleScrollThumb:
+		return StateStyles{
+			Normal: Style{Background: t.BorderColor, Radius: t.Radius},
+		}
+	case StyleDivider:
+		return StateStyles{
+			Normal:   Style{Background: t.BorderColor},
+			Hovered:  Style{Background: t.ButtonHoverColor},
+			Pressed:  Style{Background: t.PrimaryColor},
+			Selected: Style{Background: t.TextColor},
+		}
+	case StyleDrag:
+		return StateStyles{
+			Normal: Style{
+				Background:  withAlpha(t.PrimaryColor, 192),
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+				Padding:     Padding{Left: t.Padding.Left, Right: t.Padding.Right},
+			},
+		}
+	case StyleDropTarget:
+		return StateStyles{
+			Normal: Style{
+				Background:  t.ButtonColor,
+				Border:      t.BorderColor,
+				Text:        t.TextColor,
+				BorderWidth: bw,
+				Radius:      t.Radius,
+			},
+			Hovered:  Style{Background: withAlpha(t.PrimaryColor, 80), Border: t.PrimaryColor},
+			Selected: Style{Background: t.PrimaryColor},
+		}
+	default:
+		return StateStyles{
+			Normal:   Style{Text: t.TextColor, Radius: t.Radius, Padding: t.Padding},
+			Hovered:  Style{Background: t.PrimaryColor},
+			Disabled: Style{Text: t.DisabledTextColor},
+		}
+	}
+}
@@ -15,31 +15,225 @@ type TabContainer struct {
 	BaseWidget
 	Tabs      []Tab
 	ActiveTab int
+	// Reorderable tab containers let the user drag tabs to a new position,
+	// after which OnReorder is called with the old and new index of the tab.
+	Reorderable bool
+	OnReorder   func(from, to int)
+
+	dragIndex int // Tab being dragged
+	dropIndex int // Insertion point of a tab drag, or -1
+	focused   bool
 }
 
 func NewTabContainer(tabs ...Tab) *TabContainer {
 	t := &TabContainer{
 		Tabs:      tabs,
 		ActiveTab: 0,
+		dragIndex: -1,
+		dropIndex: -1,
 	}
 	t.Fill = true
+	for _, tab := range tabs {
+		adopt(t, tab.Content)
+	}
 	return t
 }
 
+func (t *TabContainer) GetChildren() []Widget {
+	var ret []Widget
+	for _, tab := range t.Tabs {
+		if tab.Content != nil {
+			ret = append(ret, tab.Content)
+		}
+	}
+	return ret
+}
+
+func (t *TabContainer) headerHeight(theme *Theme) int {
+	metrics := theme.Font.Metrics()
+	pad := theme.Style(StyleTab, 0).Padding
+	headerHeight := (metrics.Ascent + metrics.Descent).Ceil() + pad.Top + pad.Bottom
+	if theme.IconSize()+pad.Top+pad.Bottom > headerHeight {
+		headerHeight = theme.IconSize() + pad.Top + pad.Bottom
+	}
+	return headerHeight
+}
+
+func (t *TabContainer) tabWidth(theme *Theme, tab Tab) int {
+	pad := theme.Style(StyleTab, 0).Padding
+	w := font.MeasureString(theme.Font, tab.Title).Ceil() + pad.Left + pad.Right
+	if tab.Icon != IconNone {
+		w += theme.IconSize() + theme.Spacing
+	}
+	return w
+}
+
+// TabAt returns the index of the tab whose header contains pos, or -1.
+func (t *TabContainer) TabAt(pos q2d.Point) int {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil || !t.Rect.Contains(pos) {
+		return -1
+	}
+	if pos.Y()-t.Rect.Y() >= t.headerHeight(theme) {
+		return -1
+	}
+	x := t.Rect.X()
+	for i, tab := range t.Tabs {
+		w := t.tabWidth(theme, tab)
+		if pos.X() >= x && pos.X() < x+w {
+			return i
+		}
+		x += w
+	}
+	return -1
+}
+
+// insertIndexAt returns the index a tab dropped at pos is inserted at.
+func (t *TabContainer) insertIndexAt(pos q2d.Point) int {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return len(t.Tabs)
+	}
+	x := t.Rect.X()
+	for i, tab := range t.Tabs {
+		w := t.tabWidth(theme, tab)
+		if pos.X() < x+w/2 {
+			return i
+		}
+		x += w
+	}
+	return len(t.Tabs)
+}
+
+// DragStart implements DragSource for Reorderable tab containers.
+func (t *TabContainer) DragStart(pos q2d.Point) *DragData {
+	i := t.TabAt(pos)
+	if !t.Reorderable || i < 0 {
+		return nil
+	}
+	t.dragIndex = i
+	tab := t.Tabs[i]
+	return &DragData{
+		Kind:   DragKindTab,
+		Value:  tab,
+		Source: t,
+		Text:   tab.Title,
+		Icon:   tab.Icon,
+	}
+}
+
+func (t *TabContainer) DragEnd(data *DragData, accepted bool) {
+	t.dragIndex = -1
+}
+
+// DragAccept accepts the container's own tabs while it is being reordered.
+func (t *TabContainer) DragAccept(data *DragData, pos q2d.Point) bool {
+	return t.Reorderable && data.Source == t && t.dragIndex >= 0 && pos.Y() < t.HeaderRect().Y()+t.HeaderRect().Height()
+}
+
+func (t *TabContainer) DragHover(data *DragData, pos q2d.Point, over bool) {
+	t.dropIndex = -1
+	if over {
+		t.dropIndex = t.insertIndexAt(pos)
+	}
+}
+
+func (t *TabContainer) Drop(data *DragData, pos q2d.Point) bool {
+	from, to := t.dragIndex, t.insertIndexAt(pos)
+	if to > from {
+		to-- // Removing the tab shifts the insertion point left
+	}
+	if to == from {
+		return true
+	}
+	active := t.ActiveTab
+	tab := t.Tabs[from]
+	t.Tabs = append(t.Tabs[:from], t.Tabs[from+1:]...)
+	t.Tabs = append(t.Tabs[:to], append([]Tab{tab}, t.Tabs[to:]...)...)
+	// The active tab stays active
+	switch {
+	case active == from:
+		t.ActiveTab = to
+	case from < active && active <= to:
+		t.ActiveTab--
+	case to <= active && active < from:
+		t.ActiveTab++
+	}
+	t.dragIndex = to
+	t.Layout(Size{t.Rect.Width(), t.Rect.Height()})
+	if t.OnReorder != nil {
+		t.OnReorder(from, to)
+	}
+	return true
+}
+
+// HeaderRect returns the absolute rectangle of the tab header strip.
+func (t *TabContainer) HeaderRect() q2d.Rectangle {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return q2d.Rectangle{t.Rect.X(), t.Rect.Y(), t.Rect.Width(), 0}
+	}
+	return q2d.Rectangle{t.Rect.X(), t.Rect.Y(), t.Rect.Width(), t.headerHeight(theme)}
+}
+
+// navRect makes spatial navigation focus the tab headers.
+func (t *TabContainer) navRect() q2d.Rectangle {
+	return t.HeaderRect()
+}
+
+// Focus gives the tab headers the focus. The left and right arrow keys and
+// navigation switch tabs while they have it.
+func (t *TabContainer) Focus() {
+	t.focused = true
+}
+
+func (t *TabContainer) Unfocus() {
+	t.focused = false
+}
+
+// tabStep returns -1 or 1 if e asks a TabContainer with the focus to switch
+// to the previous or next tab, or 0.
+func tabStep(e Event) int {
+	switch e := e.(type) {
+	case KeyEvent:
+		if e.TypeVal == EventKeyDown && e.Key == KeyLeft {
+			return -1
+		}
+		if e.TypeVal == EventKeyDown && e.Key == KeyRight {
+			return 1
+		}
+	case NavEvent:
+		if e.Action == NavLeft {
+			return -1
+		}
+		if e.Action == NavRight {
+			return 1
+		}
+	}
+	return 0
+}
+
+// switchTab activates the tab delta away from the active one. It returns
+// false if there is no such tab.
+func (t *TabContainer) switchTab(delta int) bool {
+	i := t.ActiveTab + delta
+	if i < 0 || i >= len(t.Tabs) {
+		return false
+	}
+	t.ActiveTab = i
+	return true
+}
+
 func (t *TabContainer) MinSize() Size {
 	theme := t.GetTheme()
 	if theme == nil || theme.Font == nil {
 		return Size{0, 0}
 	}
-	metrics := theme.Font.Metrics()
-	headerHeight := (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-	if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerHeight {
-		headerHeight = IconSize + theme.Padding.Top + theme.Padding.Bottom
-	}
+	headerHeight := t.headerHeight(theme)
 
 	maxW, maxH := 0, 0
 	for _, tab := range t.Tabs {
-		if tab.Content != nil {
+		if shown(tab.Content) {
 			sz := tab.Content.MinSize()
 			if sz.Width > maxW {
 				maxW = sz.Width
@@ -53,10 +247,7 @@ func (t *TabContainer) MinSize() Size {
 	// Width must also accommodate tabs
 	tabsW := 0
 	for _, tab := range t.Tabs {
-		w := font.MeasureString(theme.Font, tab.Title).Ceil() + theme.Padding.Left + theme.Padding.Right
-		if tab.Icon != IconNone {
-			w += IconSize + theme.Spacing
-		}
+		w := t.tabWidth(theme, tab)
 		tabsW += w
 	}
 	if tabsW > maxW {
@@ -71,17 +262,16 @@ func (t *TabContainer) Layout(available Size) Size {
 	if theme == nil || theme.Font == nil {
 		return Size{0, 0}
 	}
-	metrics := theme.Font.Metrics()
-	headerHeight := (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-	if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerHeight {
-		headerHeight = IconSize + theme.Padding.Top + theme.Padding.Bottom
-	}
+	headerHeight := t.headerHeight(theme)
 
 	contentAvailable := Size{available.Width, available.Height - headerHeight}
+	for _, tab := range t.Tabs {
+		adopt(t, tab.Content)
+	}
 
 	if t.ActiveTab >= 0 && t.ActiveTab < len(t.Tabs) {
 		child := t.Tabs[t.ActiveTab].Content
-		if child != nil {
+		if shown(child) {
 			// Position child below header
 			child.SetRect(q2d.Rectangle{t.Rect.X(), t.Rect.Y() + headerHeight, contentAvailable.Width, contentAvailable.Height})
 			child.Layout(contentAvailable)
@@ -93,13 +283,14 @@ func (t *TabContainer) Layout(available Size) Size {
 
 func (t *TabContainer) Event(evt Event) bool {
 	theme := t.GetTheme()
-	if theme == nil || theme.Font == nil {
+	if theme == nil || theme.Font == nil || t.Disabled {
 		return false
 	}
-	metrics := theme.Font.Metrics()
-	headerHeight := (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-	if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerHeight {
-		headerHeight = IconSize + theme.Padding.Top + theme.Padding.Bottom
+	headerHeight := t.headerHeight(theme)
+	if t.focused {
+		if d := tabStep(evt); d != 0 {
+			return t.switchTab(d)
+		}
 	}
 
 	switch event := evt.(type) {
@@ -109,37 +300,11 @@ func (t *TabContainer) Event(evt Event) bool {
 			if relY < headerHeight {
 				// Header click
 				if event.TypeVal == EventMouseDown {
-					// Find which tab
-					x := 0
-					relX := event.Pos.X() - t.Rect.X()
-					for i, tab := range t.Tabs {
-						w := font.MeasureString(theme.Font, tab.Title).Ceil() + theme.Padding.Left + theme.Padding.Right
-						if tab.Icon != IconNone {
-							w += IconSize + theme.Spacing
-						}
-
-						if relX >= x && relX < x+w {
-							t.ActiveTab = i
-							return true
-						}
-						x += w
+					if i := t.TabAt(event.Pos); i >= 0 {
+						t.ActiveTab = i
 					}
 				}
 				return true // Consume header events
-			} else {
-				// Pass to content
-				if t.ActiveTab >= 0 && t.ActiveTab < len(t.Tabs) {
-					if t.Tabs[t.ActiveTab].Content != nil {
-						return t.Tabs[t.ActiveTab].Content.Event(evt)
-					}
-				}
-			}
-		}
-	default:
-		// Pass non-mouse events to content
-		if t.ActiveTab >= 0 && t.ActiveTab < len(t.Tabs) {
-			if t.Tabs[t.ActiveTab].Content != nil {
-				return t.Tabs[t.ActiveTab].Content.Event(evt)
 			}
 		}
 	}
@@ -153,7 +318,7 @@ func (t *TabContainer) FindWidgetAt(pos q2d.Point) Widget {
 
 	// Check content
 	if t.ActiveTab >= 0 && t.ActiveTab < len(t.Tabs) {
-		if t.Tabs[t.ActiveTab].Content != nil {
+		if shown(t.Tabs[t.ActiveTab].Content) {
 			if found := t.Tabs[t.ActiveTab].Content.FindWidgetAt(pos); found != nil {
 				return found
 			}
@@ -172,10 +337,7 @@ func (t *TabContainer) Draw(img *q2d.Image) {
 
 	metrics := theme.Font.Metrics()
 	textHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	headerHeight := textHeight + theme.Padding.Top + theme.Padding.Bottom
-	if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerHeight {
-		headerHeight = IconSize + theme.Padding.Top + theme.Padding.Bottom
-	}
+	headerHeight := t.headerHeight(theme)
 
 	// Draw Header
 	img.PushSubImage(t.Rect)
@@ -183,53 +345,59 @@ func (t *TabContainer) Draw(img *q2d.Image) {
 	// Draw Header Background
 	headerRect := q2d.Rectangle{0, 0, t.Rect.Width(), headerHeight}
 	img.PushSubImage(headerRect)
-	img.Fill(theme.BackgroundColor.Darken(0.2))
+	DrawBox(img, q2d.Rectangle{0, 0, headerRect.Width(), headerHeight}, theme.Style(StyleTabBar, t.state()))
 
 	x := 0
 	for i, tab := range t.Tabs {
-		w := font.MeasureString(theme.Font, tab.Title).Ceil() + theme.Padding.Left + theme.Padding.Right
-		if tab.Icon != IconNone {
-			w += IconSize + theme.Spacing
-		}
+		w := t.tabWidth(theme, tab)
 
-		bg := theme.ButtonColor
+		state := t.state()
 		if i == t.ActiveTab {
-			bg = theme.BackgroundColor
+			state |= StateSelected
+			if t.focused {
+				state |= StateFocused
+			}
 		}
+		st := theme.Style(StyleTab, state)
 
 		// Draw tab rect
 		tabRect := q2d.Rectangle{x, 0, w, headerHeight}
 		img.PushSubImage(tabRect)
-		img.Fill(bg)
-		img.Border(theme.BorderColor)
+		drawBox(img, q2d.Rectangle{0, 0, w, headerHeight}, st, cornersTop)
 
-		contentX := theme.Padding.Left
+		contentX := st.Padding.Left
 		if tab.Icon != IconNone {
-			iconY := (headerHeight - IconSize) / 2
-			DrawIcon(img, tab.Icon, q2d.Point{contentX, iconY}, theme.TextColor)
-			contentX += IconSize + theme.Spacing
+			iconY := (headerHeight - theme.IconSize()) / 2
+			theme.DrawIcon(img, tab.Icon, q2d.Point{contentX, iconY}, st.Text)
+			contentX += theme.IconSize() + theme.Spacing
 		}
 
 		textY := (headerHeight - textHeight) / 2
-		img.Text(q2d.Point{contentX, textY}, theme.TextColor, theme.Font, false, "%s", tab.Title)
+		img.Text(q2d.Point{contentX, textY}, st.Text, theme.Font, false, "%s", tab.Title)
 		img.PopSubImage()
 
 		x += w
 	}
+	if t.dropIndex >= 0 {
+		// Insertion mark
+		x := 0
+		for _, tab := range t.Tabs[:t.dropIndex] {
+			x += t.tabWidth(theme, tab)
+		}
+		DrawBox(img, q2d.Rectangle{max(x-1, 0), 0, theme.Px(2), headerHeight}, theme.Style(StyleDivider, StateSelected))
+	}
 	img.PopSubImage() // Header Background
 	img.PopSubImage() // t.Rect
 
 	// Draw Content Background
 	contentRect := q2d.Rectangle{0, headerHeight, t.Rect.Width(), t.Rect.Height() - headerHeight}
 	img.PushSubImage(t.Rect)
-	img.PushSubImage(contentRect)
-	img.Fill(theme.BackgroundColor.Darken(0.2))
-	img.PopSubImage()
+	DrawBox(img, contentRect, theme.Style(StyleInset, t.state()))
 	img.PopSubImage()
 
 	// Draw Content
 	if t.ActiveTab >= 0 && t.ActiveTab < len(t.Tabs) {
-		if t.Tabs[t.ActiveTab].Content != nil {
+		if shown(t.Tabs[t.ActiveTab].Content) {
 			t.Tabs[t.ActiveTab].Content.Draw(img)
 		}
 	}
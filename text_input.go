@@ -24,13 +24,17 @@ type TextInput struct {
 
 	focused   bool
 	cursorPos int
+	anchor    int     // Other end of the selection, cursorPos if none
+	preedit   preedit // Input method composition
 }
 
 func NewTextInput(initialText string, t EntryType) *TextInput {
+	n := len([]rune(initialText))
 	return &TextInput{
 		Text:      initialText,
 		Type:      t,
-		cursorPos: len([]rune(initialText)),
+		cursorPos: n,
+		anchor:    n,
 	}
 }
 
@@ -55,43 +59,60 @@ func (t *TextInput) Event(evt Event) bool {
 	case MouseEvent:
 		if event.TypeVal == EventMouseDown {
 			if t.Rect.Contains(event.Pos) {
+				t.selectAt(event.Pos, event.Clicks)
 				return true
 			}
 		}
+	case CompositionEvent:
+		if t.focused {
+			t.preedit.set(event)
+			return true
+		}
 	case TextInputEvent:
 		if t.focused {
+			t.preedit = preedit{}
 			t.insertText(event.Text)
 			return true
 		}
 	case KeyEvent:
 		if t.focused && event.TypeVal == EventKeyDown {
 			runes := []rune(t.Text)
+			start, end := t.selection()
 			switch event.Key {
 			case KeyLeft:
-				if t.cursorPos > 0 {
+				if start < end {
+					t.cursorPos = start
+				} else if t.cursorPos > 0 {
 					t.cursorPos--
 				}
+				t.anchor = t.cursorPos
 				return true
 			case KeyRight:
-				if t.cursorPos < len(runes) {
+				if start < end {
+					t.cursorPos = end
+				} else if t.cursorPos < len(runes) {
 					t.cursorPos++
 				}
+				t.anchor = t.cursorPos
 				return true
 			case KeyHome:
-				t.cursorPos = 0
+				t.cursorPos, t.anchor = 0, 0
 				return true
 			case KeyEnd:
-				t.cursorPos = len(runes)
+				t.cursorPos, t.anchor = len(runes), len(runes)
 				return true
 			case KeyBackspace:
-				if t.cursorPos > 0 {
-					t.Text = string(append(runes[:t.cursorPos-1], runes[t.cursorPos:]...))
-					t.cursorPos--
+				if start < end {
+					t.replace(start, end, nil)
+				} else if t.cursorPos > 0 {
+					t.replace(t.cursorPos-1, t.cursorPos, nil)
 				}
 				return true
 			case KeyDelete:
-				if t.cursorPos < len(runes) {
-					t.Text = string(append(runes[:t.cursorPos], runes[t.cursorPos+1:]...))
+				if start < end {
+					t.replace(start, end, nil)
+				} else if t.cursorPos < len(runes) {
+					t.replace(t.cursorPos, t.cursorPos+1, nil)
 				}
 				return true
 			}
@@ -100,20 +121,47 @@ func (t *TextInput) Event(evt Event) bool {
 	return false
 }
 
-func (t *TextInput) Focus() {
-	t.focused = true
+// selection returns the selected runes as a range, which is empty when
+// nothing is selected.
+func (t *TextInput) selection() (int, int) {
+	n := len([]rune(t.Text))
+	a, b := min(t.anchor, n), min(t.cursorPos, n)
+	return min(a, b), max(a, b)
 }
 
-func (t *TextInput) Unfocus() {
-	t.focused = false
+// SelectedText returns the selected text, if any.
+func (t *TextInput) SelectedText() string {
+	start, end := t.selection()
+	return string([]rune(t.Text)[start:end])
 }
 
-func (t *TextInput) insertText(text string) {
-	runes := []rune(t.Text)
-	insert := []rune(text)
+// selectAt places the cursor at pos for a single click. A double-click selects
+// the word at pos and a triple-click the whole text, as does a double-click in
+// a password field.
+func (t *TextInput) selectAt(pos q2d.Point, clicks int) {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return
+	}
+	runes := t.displayRunes()
+	x := pos.X() - t.Rect.X()
+	switch {
+	case clicks >= 3 || clicks == 2 && t.Type == EntryPassword:
+		t.anchor, t.cursorPos = 0, len(runes)
+	case clicks == 2:
+		t.anchor, t.cursorPos = wordBounds(runes, runeAt(runes, theme.Font, x))
+	default:
+		i := runeIndexAt(runes, theme.Font, x)
+		t.anchor, t.cursorPos = i, i
+	}
+}
 
-	// Insert at cursor
-	newRunes := append(runes[:t.cursorPos], append(insert, runes[t.cursorPos:]...)...)
+// replace replaces the runes from start to end with insert and places the
+// cursor after it. Text that is not valid for the type of the input is
+// rejected.
+func (t *TextInput) replace(start, end int, insert []rune) {
+	runes := []rune(t.Text)
+	newRunes := append(append(append([]rune{}, runes[:start]...), insert...), runes[end:]...)
 	newText := string(newRunes)
 
 	if t.Type == EntryInteger {
@@ -126,7 +174,50 @@ func (t *TextInput) insertText(text string) {
 		}
 	}
 	t.Text = newText
-	t.cursorPos += len(insert)
+	t.cursorPos = start + len(insert)
+	t.anchor = t.cursorPos
+}
+
+func (t *TextInput) Focus() {
+	t.focused = true
+}
+
+func (t *TextInput) Unfocus() {
+	t.focused = false
+	t.preedit = preedit{}
+}
+
+// displayRunes returns the text as shown, masked for passwords.
+func (t *TextInput) displayRunes() []rune {
+	if t.Type == EntryPassword {
+		return []rune(strings.Repeat("*", len([]rune(t.Text))))
+	}
+	return []rune(t.Text)
+}
+
+// cursorX returns the offset of the cursor from the left edge, ignoring any
+// preedit text.
+func (t *TextInput) cursorX(f font.Face) int {
+	runes := t.displayRunes()
+	t.cursorPos = min(t.cursorPos, len(runes))
+	return font.MeasureString(f, string(runes[:t.cursorPos])).Ceil()
+}
+
+func (t *TextInput) CaretRect() q2d.Rectangle {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return q2d.Rectangle{t.Rect.X(), t.Rect.Y(), 1, t.Rect.Height()}
+	}
+	metrics := theme.Font.Metrics()
+	x := t.cursorX(theme.Font) + t.preedit.cursorX(theme.Font)
+	return q2d.Rectangle{t.Rect.X() + x, t.Rect.Y(), 1, (metrics.Ascent + metrics.Descent).Ceil()}
+}
+
+// insertText replaces the selection, or inserts at the cursor if there is
+// none.
+func (t *TextInput) insertText(text string) {
+	start, end := t.selection()
+	t.replace(start, end, []rune(text))
 }
 
 func (t *TextInput) Draw(img *q2d.Image) {
@@ -135,30 +226,37 @@ func (t *TextInput) Draw(img *q2d.Image) {
 		return
 	}
 
-	displayText := t.Text
-	if t.Type == EntryPassword {
-		displayText = strings.Repeat("*", len([]rune(t.Text)))
-	}
-
 	img.PushSubImage(t.Rect)
 	defer img.PopSubImage()
 
-	img.Text(q2d.Point{0, 0}, theme.TextColor, theme.Font, false, "%s", displayText)
-
+	state := t.state()
 	if t.focused {
-		runes := []rune(displayText)
-		cursorX := 0
-		if t.cursorPos > 0 {
-			if t.cursorPos > len(runes) {
-				t.cursorPos = len(runes)
-			}
-			cursorX = font.MeasureString(theme.Font, string(runes[:t.cursorPos])).Ceil()
-		}
-
-		metrics := theme.Font.Metrics()
-		height := (metrics.Ascent + metrics.Descent).Ceil()
+		state |= StateFocused
+	}
+	color := theme.Style(StyleInput, state).Text
+	runes := t.displayRunes()
+	cursorX := t.cursorX(theme.Font)
+	metrics := theme.Font.Metrics()
+	height := (metrics.Ascent + metrics.Descent).Ceil()
+	if start, end := t.selection(); start < end {
+		x0 := font.MeasureString(theme.Font, string(runes[:start])).Ceil()
+		x1 := font.MeasureString(theme.Font, string(runes[:end])).Ceil()
+		DrawBox(img, q2d.Rectangle{x0, 0, x1 - x0, height}, Style{Background: theme.Style(StyleInput, StateSelected).Background})
+	}
+	if t.preedit.text == "" {
+		img.Text(q2d.Point{0, 0}, color, theme.Font, false, "%s", string(runes))
+	} else {
+		// The preedit text is shown underlined at the cursor
+		before := string(runes[:t.cursorPos])
+		img.Text(q2d.Point{0, 0}, color, theme.Font, false, "%s", before)
+		t.preedit.draw(img, q2d.Point{cursorX, 0}, theme.Font, color)
+		afterX := cursorX + font.MeasureString(theme.Font, t.preedit.text).Ceil()
+		img.Text(q2d.Point{afterX, 0}, color, theme.Font, false, "%s", string(runes[t.cursorPos:]))
+		cursorX += t.preedit.cursorX(theme.Font)
+	}
 
-		img.VLine(cursorX, 0, height, 1, theme.TextColor)
+	if t.focused {
+		img.VLine(cursorX, 0, height, 1, color)
 	}
 }
 
@@ -175,4 +273,5 @@ func (t *TextInput) GetText() string {
 
 func (t *TextInput) SetText(text string) {
 	t.Text = text
+	t.anchor = t.cursorPos
 }
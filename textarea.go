@@ -1,7 +1,10 @@
 package qui
 
 import (
+	"strings"
+
 	"github.com/qbradq/q2d"
+	"golang.org/x/image/font"
 )
 
 type TextArea struct {
@@ -11,6 +14,9 @@ type TextArea struct {
 	focused bool
 	Width   int
 	Height  int
+	preedit preedit // Input method composition
+	// Selected bytes of Text, cleared by editing
+	selStart, selEnd int
 }
 
 func NewTextArea(text string) *TextArea {
@@ -25,7 +31,7 @@ func (t *TextArea) MinSize() Size {
 	// Arbitrary minimum size
 	metrics := theme.Font.Metrics()
 	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
-	w := 100
+	w := theme.Px(100)
 	if t.Width > 0 {
 		w = t.Width
 	}
@@ -42,12 +48,20 @@ func (t *TextArea) Event(evt Event) bool {
 		if event.TypeVal == EventMouseDown {
 			// Focus handled by Master
 			if t.Rect.Contains(event.Pos) {
+				t.selectAt(event.Pos, event.Clicks)
 				return true
 			}
 		}
+	case CompositionEvent:
+		if t.focused {
+			t.preedit.set(event)
+			return true
+		}
 	case TextInputEvent:
 		if t.focused {
+			t.preedit = preedit{}
 			t.Text += event.Text
+			t.selStart, t.selEnd = 0, 0
 			return true
 		}
 	case KeyEvent:
@@ -56,9 +70,11 @@ func (t *TextArea) Event(evt Event) bool {
 				if len(t.Text) > 0 {
 					t.Text = t.Text[:len(t.Text)-1]
 				}
+				t.selStart, t.selEnd = 0, 0
 				return true
 			} else if event.Key == KeyEnter {
 				t.Text += "\n"
+				t.selStart, t.selEnd = 0, 0
 				return true
 			}
 		}
@@ -72,6 +88,122 @@ func (t *TextArea) Focus() {
 
 func (t *TextArea) Unfocus() {
 	t.focused = false
+	t.preedit = preedit{}
+}
+
+// selection returns the selected bytes of the text as a range, which is empty
+// when nothing is selected.
+func (t *TextArea) selection() (int, int) {
+	return min(t.selStart, len(t.Text)), min(t.selEnd, len(t.Text))
+}
+
+// SelectedText returns the selected text, if any.
+func (t *TextArea) SelectedText() string {
+	start, end := t.selection()
+	return t.Text[start:end]
+}
+
+// lines returns the text wrapped the way it is drawn and the height of a line.
+func (t *TextArea) lines(theme *Theme) ([]wrappedLine, int) {
+	metrics := theme.Font.Metrics()
+	pad := t.style(theme).Padding
+	return wrapWords(t.Text, theme.Font, t.Rect.Width()-pad.Left), (metrics.Ascent + metrics.Descent).Ceil()
+}
+
+// selectAt clears the selection for a single click. A double-click selects
+// the word at pos and a triple-click the line of text it is on.
+func (t *TextArea) selectAt(pos q2d.Point, clicks int) {
+	t.selStart, t.selEnd = 0, 0
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil || clicks < 2 {
+		return
+	}
+	pad := t.style(theme).Padding
+	lines, lineHeight := t.lines(theme)
+	line := lines[min(max((pos.Y()-t.Rect.Y()-pad.Top)/lineHeight, 0), len(lines)-1)]
+	if clicks >= 3 {
+		t.selStart, t.selEnd = line.para.start, line.para.end
+		return
+	}
+	if len(line.words) == 0 {
+		return
+	}
+	// The word starting last before pos
+	x := pos.X() - t.Rect.X() - pad.Left
+	i := 0
+	for i+1 < len(line.words) && line.wordX(t.Text, theme.Font, i+1) <= x {
+		i++
+	}
+	w := line.words[i]
+	runes := []rune(t.Text[w.start:w.end])
+	start, end := wordBounds(runes, runeAt(runes, theme.Font, x-line.wordX(t.Text, theme.Font, i)))
+	t.selStart = w.start + len(string(runes[:start]))
+	t.selEnd = w.start + len(string(runes[:end]))
+}
+
+// drawSelection highlights the selected text on each line it spans.
+func (t *TextArea) drawSelection(img *q2d.Image, theme *Theme) {
+	start, end := t.selection()
+	if start >= end {
+		return
+	}
+	pad := t.style(theme).Padding
+	bg := Style{Background: theme.Style(StyleInput, StateSelected).Background}
+	lines, lineHeight := t.lines(theme)
+	for k, line := range lines {
+		x0, x1 := -1, -1
+		for i, w := range line.words {
+			s, e := max(w.start, start), min(w.end, end)
+			if s >= e {
+				continue
+			}
+			x := line.wordX(t.Text, theme.Font, i)
+			if x0 < 0 {
+				x0 = x + font.MeasureString(theme.Font, t.Text[w.start:s]).Ceil()
+			}
+			x1 = x + font.MeasureString(theme.Font, t.Text[w.start:e]).Ceil()
+		}
+		if x0 >= 0 {
+			DrawBox(img, q2d.Rectangle{pad.Left + x0, pad.Top + k*lineHeight, x1 - x0, lineHeight}, bg)
+		}
+	}
+}
+
+// style returns the style of the text area in its current state.
+func (t *TextArea) style(theme *Theme) Style {
+	state := t.state()
+	if t.focused {
+		state |= StateFocused
+	}
+	return theme.Style(StyleInput, state)
+}
+
+// endPos returns where the text ends, relative to the widget, which is where
+// the cursor and any preedit text are.
+func (t *TextArea) endPos(theme *Theme) q2d.Point {
+	metrics := theme.Font.Metrics()
+	lineHeight := (metrics.Ascent + metrics.Descent).Ceil()
+	pad := t.style(theme).Padding
+	lines := wrapText(t.Text, theme.Font, t.Rect.Width()-pad.Left)
+	last := lines[len(lines)-1]
+	if last != "" {
+		// Wrapping drops trailing spaces
+		para := t.Text[strings.LastIndex(t.Text, "\n")+1:]
+		last += para[len(strings.TrimRight(para, " ")):]
+	}
+	x := font.MeasureString(theme.Font, last).Ceil()
+	return q2d.Point{pad.Left + x, pad.Top + (len(lines)-1)*lineHeight}
+}
+
+func (t *TextArea) CaretRect() q2d.Rectangle {
+	theme := t.GetTheme()
+	if theme == nil || theme.Font == nil {
+		return q2d.Rectangle{t.Rect.X(), t.Rect.Y(), 1, t.Rect.Height()}
+	}
+	metrics := theme.Font.Metrics()
+	p := t.endPos(theme)
+	x := p.X() + t.preedit.cursorX(theme.Font)
+	return q2d.Rectangle{t.Rect.X() + x, t.Rect.Y() + p.Y(), 1, (metrics.Ascent + metrics.Descent).Ceil()}
 }
 
 func (t *TextArea) FindWidgetAt(pos q2d.Point) Widget {
@@ -90,18 +222,30 @@ func (t *TextArea) Draw(img *q2d.Image) {
 	img.PushSubImage(t.Rect)
 	defer img.PopSubImage()
 
-	bgColor := theme.BackgroundColor.Darken(0.1)
-	if t.focused {
-		bgColor = theme.BackgroundColor.Lighten(0.1)
+	st := t.style(theme)
+	DrawBox(img, q2d.Rectangle{0, 0, t.Rect.Width(), t.Rect.Height()}, st)
+	if theme.Font != nil {
+		t.drawSelection(img, theme)
 	}
 
-	img.Fill(bgColor)
-	img.Border(theme.BorderColor)
+	color := st.Text
+	if t.preedit.text != "" {
+		// The preedit text is shown underlined after the text, followed by
+		// the cursor
+		img.Text(q2d.Point{st.Padding.Left, st.Padding.Top}, color, theme.Font, true, "%s", t.Text)
+		p := t.endPos(theme)
+		t.preedit.draw(img, p, theme.Font, color)
+		if t.focused {
+			c := t.CaretRect()
+			img.VLine(c.X()-t.Rect.X(), p.Y(), p.Y()+c.Height(), 1, color)
+		}
+		return
+	}
 
 	displayText := t.Text
 	if t.focused {
 		displayText += "|"
 	}
 
-	img.Text(q2d.Point{theme.Padding.Left, theme.Padding.Top}, theme.TextColor, theme.Font, true, "%s", displayText)
+	img.Text(q2d.Point{st.Padding.Left, st.Padding.Top}, color, theme.Font, true, "%s", displayText)
 }
@@ -1,6 +1,8 @@
 package qui
 
 import (
+	"reflect"
+
 	"github.com/qbradq/q2d"
 	"golang.org/x/image/font"
 )
@@ -13,61 +15,162 @@ type Theme struct {
 	BorderColor      q2d.Color
 	PrimaryColor     q2d.Color
 	SecondaryColor   q2d.Color
-	Font             font.Face
-	IconSheet        *q2d.Image
-	Spacing          int
-	Padding          Padding
+	// Colors of disabled widgets
+	DisabledColor     q2d.Color
+	DisabledTextColor q2d.Color
+	Font              font.Face
+	IconSheet         *q2d.Image
+	// IconSheet2x is an optional icon sheet with icons twice the size, used
+	// in place of IconSheet when the UI is scaled up, see Master.SetScale.
+	IconSheet2x *q2d.Image
+	// FontFile and FontSize are where Font was loaded from and its size in
+	// pixels, and IconSheetFile and IconSheet2xFile are where the icon sheets
+	// were loaded from, as set by LoadTheme and written by Theme.Save. Fonts
+	// loaded from files are loaded again at the scaled size.
+	FontFile        string
+	FontSize        float64
+	IconSheetFile   string
+	IconSheet2xFile string
+	Spacing         int
+	Padding         Padding
+	// Radius rounds the corners of widgets and BorderWidth is the width of
+	// their borders, unless their style sets its own. A BorderWidth of zero
+	// keeps the borders of widgets 1 pixel and the frames of windows 2 pixels
+	// wide, and a negative one draws no borders.
+	Radius      int
+	BorderWidth int
+	// Shadow is drawn under windows shown as overlays, popup menus, tooltips
+	// and the drop down of Select.
+	Shadow Shadow
+	// Styles override the look of kinds of widgets in each state, see
+	// Theme.Style.
+	Styles Styles
+
+	scale float64   // See Master.SetScale
+	fonts fontCache // Faces of the scaled fonts
 }
 
 var DefaultTheme *Theme
 
+// Merge returns a copy of t with the fields of o that are not zero copied over
+// it, so that o can override only some of t's values, such as just
+// PrimaryColor. Structs like Padding are merged field by field. Either theme
+// may be nil. Zero means unset, so numbers such as Spacing and the sides of
+// Padding cannot be overridden with 0. Use a negative BorderWidth to remove
+// borders.
+func (t *Theme) Merge(o *Theme) *Theme {
+	if t == nil {
+		return o
+	}
+	if o == nil {
+		return t
+	}
+	ret := *t
+	mergeValue(reflect.ValueOf(&ret).Elem(), reflect.ValueOf(o).Elem())
+	return &ret
+}
+
+// mergeValue sets dst to the parts of src that are not zero.
+func mergeValue(dst, src reflect.Value) {
+	if src.Kind() == reflect.Struct {
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				mergeValue(dst.Field(i), src.Field(i))
+			}
+		}
+		return
+	}
+	if !src.IsZero() {
+		dst.Set(src)
+	}
+}
+
 func InitTheme(f font.Face) {
 	DefaultTheme = &Theme{
-		BackgroundColor:  q2d.Color{30, 30, 30, 255},
-		TextColor:        q2d.Color{220, 220, 220, 255},
-		ButtonColor:      q2d.Color{60, 60, 60, 255},
-		ButtonHoverColor: q2d.Color{80, 80, 80, 255},
-		BorderColor:      q2d.Color{100, 100, 100, 255},
-		PrimaryColor:     q2d.Color{0, 140, 255, 255},
-		SecondaryColor:   q2d.Color{50, 50, 50, 255},
-		Font:             f,
-		IconSheet:        CreateDummyIconSheet(),
-		Spacing:          5,
-		Padding:          Padding{Top: 2, Right: 5, Bottom: 2, Left: 5},
+		BackgroundColor:   q2d.Color{30, 30, 30, 255},
+		TextColor:         q2d.Color{220, 220, 220, 255},
+		ButtonColor:       q2d.Color{60, 60, 60, 255},
+		ButtonHoverColor:  q2d.Color{80, 80, 80, 255},
+		BorderColor:       q2d.Color{100, 100, 100, 255},
+		PrimaryColor:      q2d.Color{0, 140, 255, 255},
+		SecondaryColor:    q2d.Color{50, 50, 50, 255},
+		DisabledColor:     q2d.Color{45, 45, 45, 255},
+		DisabledTextColor: q2d.Color{110, 110, 110, 255},
+		Font:              f,
+		IconSheet:         CreateDummyIconSheet(),
+		Spacing:           5,
+		Padding:           Padding{Top: 2, Right: 5, Bottom: 2, Left: 5},
 	}
 }
 
+// GenerateTheme generates a theme from a background, text and accent color.
+// The colors of buttons and borders are derived from base, lighter than it
+// for dark themes and darker for light ones.
 func GenerateTheme(base, text, complement q2d.Color, f font.Face) *Theme {
+	if Luminance(base) > 0.5 {
+		return &Theme{
+			BackgroundColor:   base,
+			TextColor:         text,
+			ButtonColor:       base.Darken(0.08),
+			ButtonHoverColor:  base.Darken(0.15),
+			BorderColor:       base.Darken(0.3),
+			PrimaryColor:      complement,
+			SecondaryColor:    base.Darken(0.04),
+			DisabledColor:     base.Darken(0.04),
+			DisabledTextColor: text.Lighten(0.5),
+			Font:              f,
+			IconSheet:         CreateDummyIconSheet(),
+			Spacing:           5,
+			Padding:           Padding{Top: 2, Right: 5, Bottom: 2, Left: 5},
+		}
+	}
 	return &Theme{
-		BackgroundColor:  base,
-		TextColor:        text,
-		ButtonColor:      base.Lighten(0.1),
-		ButtonHoverColor: base.Lighten(0.2),
-		BorderColor:      base.Lighten(0.3),
-		PrimaryColor:     complement,
-		SecondaryColor:   base.Lighten(0.05),
-		Font:             f,
-		IconSheet:        CreateDummyIconSheet(),
-		Spacing:          5,
-		Padding:          Padding{Top: 2, Right: 5, Bottom: 2, Left: 5},
+		BackgroundColor:   base,
+		TextColor:         text,
+		ButtonColor:       base.Lighten(0.1),
+		ButtonHoverColor:  base.Lighten(0.2),
+		BorderColor:       base.Lighten(0.3),
+		PrimaryColor:      complement,
+		SecondaryColor:    base.Lighten(0.05),
+		DisabledColor:     base.Lighten(0.05),
+		DisabledTextColor: text.Darken(0.5),
+		Font:              f,
+		IconSheet:         CreateDummyIconSheet(),
+		Spacing:           5,
+		Padding:           Padding{Top: 2, Right: 5, Bottom: 2, Left: 5},
 	}
 }
 
 // GenerateThemeFromColor generates a theme based on a single color.
 // It uses HSL adjustments to create a dark background and appropriate accents.
+// See GenerateThemeVariant for light themes.
 func GenerateThemeFromColor(c q2d.Color, f font.Face) *Theme {
-	// Base is a darkened version of the input color
-	base := c.Darken(0.85)
+	return GenerateThemeVariant(c, ThemeDark, f)
+}
 
-	// Text is high contrast (white for dark backgrounds)
-	text := q2d.Color{240, 240, 240, 255}
+// ThemeVariant selects between dark and light generated themes.
+type ThemeVariant int
 
-	// Complement/Primary is the input color (bright)
-	// We ensure it has high saturation and lightness for "bright form"
-	// But if input is already "full-intensity", we can just use it.
-	// Or we can force it to be bright?
-	// Let's just use c as requested "bright form of the base color for comp".
-	comp := c
+const (
+	// ThemeDark has light text on a dark background.
+	ThemeDark ThemeVariant = iota
+	// ThemeLight has dark text on a light background.
+	ThemeLight
+)
 
-	return GenerateTheme(base, text, comp, f)
+// GenerateThemeVariant generates a dark or light theme from a single color.
+// The background is a very dark or very light shade of c, the accent is c
+// itself and the text color is picked to be readable on the background with
+// ReadableTextColor. Use Theme.CheckContrast to see if the result is
+// accessible.
+func GenerateThemeVariant(c q2d.Color, v ThemeVariant, f font.Face) *Theme {
+	// Base is a darkened or lightened version of the input color
+	base := c.Darken(0.85)
+	if v == ThemeLight {
+		h, s, _ := c.ToHSL()
+		base = q2d.FromHSL(h, s*0.4, 0.95, c.A())
+	}
+
+	// Complement/Primary is the input color (bright)
+	return GenerateTheme(base, ReadableTextColor(base), c, f)
 }
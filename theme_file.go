@@ -0,0 +1,454 @@
+package qui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/qbradq/q2d"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// ThemeFormat is the file format of a theme, see LoadTheme.
+type ThemeFormat int
+
+const (
+	ThemeJSON ThemeFormat = iota
+	ThemeTOML
+)
+
+// themeFormatOf returns the format of a theme file from its extension.
+func themeFormatOf(path string) (ThemeFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ThemeJSON, nil
+	case ".toml":
+		return ThemeTOML, nil
+	}
+	return 0, fmt.Errorf("qui: unknown theme format %q", filepath.Ext(path))
+}
+
+// ThemeError is an invalid value in a theme file.
+type ThemeError struct {
+	// File is the theme file, if known.
+	File string
+	// Key is the dotted path to the value, such as
+	// "styles.button.hovered.background".
+	Key string
+	Err error
+}
+
+func (e *ThemeError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("qui: theme key %s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("qui: %s: theme key %s: %v", e.File, e.Key, e.Err)
+}
+
+func (e *ThemeError) Unwrap() error {
+	return e.Err
+}
+
+// Fields of Theme that are not written to theme files as they are, but as
+// the font, icon_sheet and icon_sheet_2x keys.
+var themeFileSkip = []string{"Font", "IconSheet", "IconSheet2x", "FontFile", "FontSize", "IconSheetFile", "IconSheet2xFile"}
+
+// LoadTheme loads a theme from a JSON or TOML file, chosen by the extension
+// of path. Keys are the names of the fields of Theme in snake case, colors
+// are written as "#rrggbb" or "#rrggbbaa" and paths to the font and the icon
+// sheet are relative to the directory of the file:
+//
+//	primary_color = "#008cff"
+//	spacing = 5
+//	padding = { top = 2, right = 5, bottom = 2, left = 5 }
+//	font = { file = "fonts/Inter.ttf", size = 14 }
+//	icon_sheet = "icons.png"
+//	icon_sheet_2x = "icons@2x.png"
+//
+//	[styles.button.hovered]
+//	background = "#505050"
+//
+// Keys left out of the file are left at zero, so that the theme can be merged
+// over another with Theme.Merge. Invalid values are reported as a
+// *ThemeError.
+func LoadTheme(path string) (*Theme, error) {
+	format, err := themeFormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := ReadTheme(f, format, filepath.Dir(path))
+	if te, ok := err.(*ThemeError); ok {
+		te.File = path
+	}
+	return t, err
+}
+
+// ReadTheme reads a theme in format from r, see LoadTheme. Paths in the theme
+// are relative to dir.
+func ReadTheme(r io.Reader, format ThemeFormat, dir string) (*Theme, error) {
+	var m map[string]any
+	var err error
+	switch format {
+	case ThemeJSON:
+		err = json.NewDecoder(r).Decode(&m)
+	case ThemeTOML:
+		_, err = toml.NewDecoder(r).Decode(&m)
+	default:
+		return nil, fmt.Errorf("qui: unknown theme format %d", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("qui: parsing theme: %w", err)
+	}
+
+	t := &Theme{}
+	tv := reflect.ValueOf(t).Elem()
+	for _, key := range sortedKeys(m) {
+		v := m[key]
+		switch key {
+		case "font":
+			err = loadThemeFont(t, v, dir)
+		case "icon_sheet":
+			t.IconSheet, t.IconSheetFile, err = loadThemeIconSheet(key, v, dir)
+		case "icon_sheet_2x":
+			t.IconSheet2x, t.IconSheet2xFile, err = loadThemeIconSheet(key, v, dir)
+		default:
+			field, ok := structField(tv, key, themeFileSkip)
+			if !ok {
+				return nil, &ThemeError{Key: key, Err: errors.New("unknown key")}
+			}
+			err = decodeThemeValue(tv.FieldByIndex(field.Index), field, v, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Save writes the theme to a JSON or TOML file, chosen by the extension of
+// path. Values that are zero are left out. The font and icon sheets are only
+// written if they were loaded from files, see FontFile and IconSheetFile.
+func (t *Theme) Save(path string) error {
+	format, err := themeFormatOf(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := t.write(&buf, format, filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Write writes the theme to w in format, see Save.
+func (t *Theme) Write(w io.Writer, format ThemeFormat) error {
+	return t.write(w, format, "")
+}
+
+// write writes the theme with paths relative to dir if it is set.
+func (t *Theme) write(w io.Writer, format ThemeFormat, dir string) error {
+	m := encodeThemeStruct(reflect.ValueOf(t).Elem(), themeFileSkip)
+	if t.FontFile != "" {
+		m["font"] = map[string]any{"file": relPath(dir, t.FontFile), "size": t.FontSize}
+	}
+	if t.IconSheetFile != "" {
+		m["icon_sheet"] = relPath(dir, t.IconSheetFile)
+	}
+	if t.IconSheet2xFile != "" {
+		m["icon_sheet_2x"] = relPath(dir, t.IconSheet2xFile)
+	}
+	switch format {
+	case ThemeJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	case ThemeTOML:
+		return toml.NewEncoder(w).Encode(m)
+	}
+	return fmt.Errorf("qui: unknown theme format %d", format)
+}
+
+// keyName returns the theme file key of a field, BorderWidth becoming
+// border_width.
+func keyName(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// structField returns the field of struct v named by key.
+func structField(v reflect.Value, key string, skip []string) (reflect.StructField, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.IsExported() && !slices.Contains(skip, f.Name) && keyName(f.Name) == key {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+var (
+	colorType   = reflect.TypeOf(q2d.Color{})
+	paddingType = reflect.TypeOf(Padding{})
+)
+
+// decodeThemeValue sets dst, the value of field, to v read from key.
+func decodeThemeValue(dst reflect.Value, field reflect.StructField, v any, key string) error {
+	fail := func(format string, args ...any) error {
+		return &ThemeError{Key: key, Err: fmt.Errorf(format, args...)}
+	}
+	switch {
+	case dst.Type() == colorType:
+		c, err := parseColor(v)
+		if err != nil {
+			return &ThemeError{Key: key, Err: err}
+		}
+		dst.Set(reflect.ValueOf(c))
+		return nil
+	case dst.Type() == paddingType:
+		// A single number pads all sides
+		if n, ok := toNumber(v); ok {
+			if n != math.Trunc(n) || n < 0 {
+				return fail("want a whole number of pixels that is not negative, got %v", v)
+			}
+			p := int(n)
+			dst.Set(reflect.ValueOf(Padding{p, p, p, p}))
+			return nil
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Int:
+		n, ok := toNumber(v)
+		if !ok || n != math.Trunc(n) {
+			return fail("want a whole number, got %v", v)
+		}
+		// Only border widths have a meaning when negative
+		if n < 0 && field.Name != "BorderWidth" {
+			return fail("must not be negative, got %v", v)
+		}
+		dst.SetInt(int64(n))
+	case reflect.Float64:
+		n, ok := toNumber(v)
+		if !ok {
+			return fail("want a number, got %v", v)
+		}
+		dst.SetFloat(n)
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fail("want a table, got %v", v)
+		}
+		for _, k := range sortedKeys(m) {
+			f, ok := structField(dst, k, nil)
+			if !ok {
+				return &ThemeError{Key: key + "." + k, Err: errors.New("unknown key")}
+			}
+			if err := decodeThemeValue(dst.FieldByIndex(f.Index), f, m[k], key+"."+k); err != nil {
+				return err
+			}
+		}
+	default:
+		return fail("cannot be set in a theme file")
+	}
+	return nil
+}
+
+// toNumber returns v as a number, which is a float64 when decoded from JSON
+// and an int64 or float64 from TOML.
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// parseColor parses a color written as "#rrggbb", "#rrggbbaa" or a list of
+// three or four numbers from 0 to 255.
+func parseColor(v any) (q2d.Color, error) {
+	switch c := v.(type) {
+	case string:
+		s, ok := strings.CutPrefix(c, "#")
+		if ok && (len(s) == 6 || len(s) == 8) {
+			n, err := strconv.ParseUint(s, 16, 32)
+			if err == nil {
+				if len(s) == 6 {
+					n = n<<8 | 0xff
+				}
+				return q2d.Color{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+			}
+		}
+	case []any:
+		if len(c) == 3 || len(c) == 4 {
+			ret := q2d.Color{0, 0, 0, 255}
+			for i, e := range c {
+				n, ok := toNumber(e)
+				if !ok || n != math.Trunc(n) || n < 0 || n > 255 {
+					return q2d.Color{}, fmt.Errorf("want color components from 0 to 255, got %v", e)
+				}
+				ret[i] = uint8(n)
+			}
+			return ret, nil
+		}
+	}
+	return q2d.Color{}, fmt.Errorf("want a color like \"#rrggbb\" or \"#rrggbbaa\", got %v", v)
+}
+
+func formatColor(c q2d.Color) string {
+	if c.A() == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R(), c.G(), c.B())
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R(), c.G(), c.B(), c.A())
+}
+
+// loadThemeFont loads the font table of a theme file.
+func loadThemeFont(t *Theme, v any, dir string) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return &ThemeError{Key: "font", Err: fmt.Errorf("want a table with file and size, got %v", v)}
+	}
+	for _, k := range sortedKeys(m) {
+		if k != "file" && k != "size" {
+			return &ThemeError{Key: "font." + k, Err: errors.New("unknown key")}
+		}
+	}
+	file, ok := m["file"].(string)
+	if !ok || file == "" {
+		return &ThemeError{Key: "font.file", Err: errors.New("want the path of a TTF or OTF file")}
+	}
+	size, ok := toNumber(m["size"])
+	if !ok || size <= 0 {
+		return &ThemeError{Key: "font.size", Err: fmt.Errorf("want a size in pixels greater than 0, got %v", m["size"])}
+	}
+	path := resolvePath(dir, file)
+	face, err := loadFontFile(path, size)
+	if err != nil {
+		return &ThemeError{Key: "font.file", Err: err}
+	}
+	t.Font = face
+	t.FontFile = path
+	t.FontSize = size
+	return nil
+}
+
+// loadFontFile loads a TTF or OTF font at size pixels.
+func loadFontFile(path string, size float64) (font.Face, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// loadThemeIconSheet loads an icon sheet of a theme file and returns it with
+// its path.
+func loadThemeIconSheet(key string, v any, dir string) (*q2d.Image, string, error) {
+	file, ok := v.(string)
+	if !ok || file == "" {
+		return nil, "", &ThemeError{Key: key, Err: errors.New("want the path of a PNG file")}
+	}
+	path := resolvePath(dir, file)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", &ThemeError{Key: key, Err: err}
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, "", &ThemeError{Key: key, Err: fmt.Errorf("%s: %w", path, err)}
+	}
+	b := img.Bounds()
+	if b.Dx() != b.Dy() || b.Dx() == 0 || b.Dx()%IconsPerRow != 0 {
+		return nil, "", &ThemeError{Key: key, Err: fmt.Errorf("%s: want a square image with %d icons to a row, got %dx%d", path, IconsPerRow, b.Dx(), b.Dy())}
+	}
+	return ImageToQ2D(img), path, nil
+}
+
+func resolvePath(dir, path string) string {
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// relPath returns path relative to dir where possible.
+func relPath(dir, path string) string {
+	if dir == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// encodeThemeStruct returns the fields of struct v that are not zero as a map
+// of theme file keys.
+func encodeThemeStruct(v reflect.Value, skip []string) map[string]any {
+	m := make(map[string]any)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		fv := v.Field(i)
+		if !f.IsExported() || slices.Contains(skip, f.Name) || fv.IsZero() {
+			continue
+		}
+		key := keyName(f.Name)
+		switch {
+		case fv.Type() == colorType:
+			m[key] = formatColor(fv.Interface().(q2d.Color))
+		case fv.Kind() == reflect.Int:
+			m[key] = fv.Int()
+		case fv.Kind() == reflect.Float64:
+			m[key] = fv.Float()
+		case fv.Kind() == reflect.Struct:
+			m[key] = encodeThemeStruct(fv, nil)
+		}
+	}
+	return m
+}
@@ -0,0 +1,179 @@
+package qui
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qbradq/q2d"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		v    any
+		want q2d.Color
+		ok   bool
+	}{
+		{"#ff8000", q2d.Color{255, 128, 0, 255}, true},
+		{"#FF800080", q2d.Color{255, 128, 0, 128}, true},
+		{"#00000000", q2d.Color{0, 0, 0, 0}, true},
+		{[]any{float64(1), float64(2), float64(3)}, q2d.Color{1, 2, 3, 255}, true},
+		{[]any{int64(1), int64(2), int64(3), int64(4)}, q2d.Color{1, 2, 3, 4}, true},
+		{"ff8000", q2d.Color{}, false},
+		{"#ff80", q2d.Color{}, false},
+		{"#ff80001", q2d.Color{}, false},
+		{"#gg8000", q2d.Color{}, false},
+		{"red", q2d.Color{}, false},
+		{[]any{float64(256), float64(0), float64(0)}, q2d.Color{}, false},
+		{[]any{float64(-1), float64(0), float64(0)}, q2d.Color{}, false},
+		{[]any{float64(1.5), float64(0), float64(0)}, q2d.Color{}, false},
+		{[]any{float64(1), float64(2)}, q2d.Color{}, false},
+		{[]any{"1", "2", "3"}, q2d.Color{}, false},
+		{float64(42), q2d.Color{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseColor(tt.v)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseColor(%#v) error %v, want ok %v", tt.v, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseColor(%#v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestReadTheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ThemeFormat
+		text   string
+		check  func(*Theme) bool
+	}{
+		{"toml color", ThemeTOML, `primary_color = "#008cff"`, func(t *Theme) bool {
+			return t.PrimaryColor == q2d.Color{0, 140, 255, 255}
+		}},
+		{"json color list", ThemeJSON, `{"text_color": [10, 20, 30, 40]}`, func(t *Theme) bool {
+			return t.TextColor == q2d.Color{10, 20, 30, 40}
+		}},
+		{"toml numbers", ThemeTOML, "spacing = 5\nradius = 3\nborder_width = -1", func(t *Theme) bool {
+			return t.Spacing == 5 && t.Radius == 3 && t.BorderWidth == -1
+		}},
+		{"json numbers", ThemeJSON, `{"spacing": 5, "border_width": 2}`, func(t *Theme) bool {
+			return t.Spacing == 5 && t.BorderWidth == 2
+		}},
+		{"padding table", ThemeTOML, "padding = { top = 1, right = 2, bottom = 3, left = 4 }", func(t *Theme) bool {
+			return t.Padding == Padding{1, 2, 3, 4}
+		}},
+		{"padding number", ThemeJSON, `{"padding": 6}`, func(t *Theme) bool {
+			return t.Padding == Padding{6, 6, 6, 6}
+		}},
+		{"shadow", ThemeTOML, "[shadow]\ncolor = \"#0000008c\"\noffset_x = 2\noffset_y = 3\nblur = 8", func(t *Theme) bool {
+			return t.Shadow == Shadow{Color: q2d.Color{0, 0, 0, 140}, OffsetX: 2, OffsetY: 3, Blur: 8}
+		}},
+		{"style", ThemeTOML, "[styles.button.hovered]\nbackground = \"#505050\"\nborder_width = -1", func(t *Theme) bool {
+			st := t.Styles.Button.Hovered
+			return st.Background == q2d.Color{80, 80, 80, 255} && st.BorderWidth == -1
+		}},
+		{"style kind with two words", ThemeJSON, `{"styles": {"scroll_thumb": {"pressed": {"radius": 4}}}}`, func(t *Theme) bool {
+			return t.Styles.ScrollThumb.Pressed.Radius == 4
+		}},
+		{"empty", ThemeTOML, "", func(t *Theme) bool {
+			return reflect.DeepEqual(*t, Theme{})
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ReadTheme(strings.NewReader(tt.text), tt.format, "")
+		if err != nil {
+			t.Errorf("%s: ReadTheme error: %v", tt.name, err)
+			continue
+		}
+		if !tt.check(got) {
+			t.Errorf("%s: ReadTheme(%q) = %+v", tt.name, tt.text, got)
+		}
+	}
+}
+
+func TestReadThemeErrors(t *testing.T) {
+	tests := []struct {
+		format ThemeFormat
+		text   string
+		key    string
+	}{
+		{ThemeTOML, `colour = "#ffffff"`, "colour"},
+		{ThemeTOML, `font_file = "x.ttf"`, "font_file"},
+		{ThemeTOML, `primary_color = "blue"`, "primary_color"},
+		{ThemeJSON, `{"text_color": [1, 2, 300]}`, "text_color"},
+		{ThemeTOML, `spacing = -1`, "spacing"},
+		{ThemeJSON, `{"spacing": 1.5}`, "spacing"},
+		{ThemeTOML, `spacing = "5"`, "spacing"},
+		{ThemeTOML, `padding = -2`, "padding"},
+		{ThemeTOML, `padding = { top = -1 }`, "padding.top"},
+		{ThemeTOML, `padding = { up = 1 }`, "padding.up"},
+		{ThemeTOML, `shadow = "#000000"`, "shadow"},
+		{ThemeTOML, "[shadow]\nblur = -4", "shadow.blur"},
+		{ThemeTOML, "[styles.buton.normal]\nradius = 2", "styles.buton"},
+		{ThemeTOML, "[styles.button.active]\nradius = 2", "styles.button.active"},
+		{ThemeTOML, "[styles.button.hovered]\nbackground = \"#zzzzzz\"", "styles.button.hovered.background"},
+		{ThemeJSON, `{"styles": {"list_item": {"normal": {"padding": {"left": 0.5}}}}}`, "styles.list_item.normal.padding.left"},
+		{ThemeTOML, `font = "x.ttf"`, "font"},
+		{ThemeTOML, `font = { size = 12 }`, "font.file"},
+		{ThemeTOML, `font = { file = "x.ttf", size = 0 }`, "font.size"},
+		{ThemeTOML, `font = { file = "x.ttf", size = 12, weight = 700 }`, "font.weight"},
+		{ThemeTOML, `font = { file = "missing.ttf", size = 12 }`, "font.file"},
+		{ThemeTOML, `icon_sheet = 5`, "icon_sheet"},
+		{ThemeTOML, `icon_sheet_2x = "missing.png"`, "icon_sheet_2x"},
+	}
+	for _, tt := range tests {
+		_, err := ReadTheme(strings.NewReader(tt.text), tt.format, t.TempDir())
+		var te *ThemeError
+		if !errors.As(err, &te) {
+			t.Errorf("ReadTheme(%q) error %v, want a ThemeError", tt.text, err)
+			continue
+		}
+		if te.Key != tt.key {
+			t.Errorf("ReadTheme(%q) error key %q, want %q", tt.text, te.Key, tt.key)
+		}
+	}
+}
+
+func TestReadThemeSyntaxError(t *testing.T) {
+	for _, format := range []ThemeFormat{ThemeJSON, ThemeTOML} {
+		_, err := ReadTheme(strings.NewReader("{spacing = "), format, "")
+		var te *ThemeError
+		if err == nil || errors.As(err, &te) {
+			t.Errorf("ReadTheme in format %d: error %v, want a parse error", format, err)
+		}
+	}
+}
+
+func TestWriteThemeRoundTrip(t *testing.T) {
+	want := &Theme{
+		BackgroundColor: q2d.Color{30, 30, 30, 255},
+		PrimaryColor:    q2d.Color{0, 140, 255, 200},
+		Spacing:         4,
+		Padding:         Padding{1, 2, 3, 4},
+		Radius:          3,
+		BorderWidth:     -1,
+		Shadow:          Shadow{Color: q2d.Color{0, 0, 0, 140}, OffsetX: 2, OffsetY: 3, Blur: 8},
+	}
+	want.Styles.Button.Hovered = Style{Background: q2d.Color{80, 80, 80, 255}, Gradient: q2d.Color{40, 40, 40, 255}}
+	want.Styles.Header.Normal.Padding = Padding{Left: 8}
+	want.Styles.Divider.Pressed.BorderWidth = -1
+
+	for _, format := range []ThemeFormat{ThemeJSON, ThemeTOML} {
+		var buf bytes.Buffer
+		if err := want.Write(&buf, format); err != nil {
+			t.Fatalf("Write in format %d: %v", format, err)
+		}
+		got, err := ReadTheme(&buf, format, "")
+		if err != nil {
+			t.Fatalf("ReadTheme in format %d: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip in format %d:\ngot  %+v\nwant %+v", format, got, want)
+		}
+	}
+}
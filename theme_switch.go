@@ -0,0 +1,179 @@
+package qui
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/qbradq/q2d"
+)
+
+// ThemeListener is implemented by widgets that keep values derived from their
+// theme, such as measured text, to be told when the theme of their Master
+// changes.
+type ThemeListener interface {
+	ThemeChanged()
+}
+
+// themeFade is a cross-fade from one theme to Master.Theme.
+type themeFade struct {
+	from     *Theme
+	start    time.Time
+	duration time.Duration
+	current  *Theme // Blend as of the last step
+}
+
+// SetTheme changes the theme of the root and the overlays. Every widget is
+// told with a ThemeEvent to its handlers and by ThemeChanged if it is a
+// ThemeListener, and the UI is laid out again if the font or padding changed.
+// With a fade duration the colors blend from the old theme to t over that
+// time, for which the host has to keep drawing. The font and sizes change
+// right away.
+func (m *Master) SetTheme(t *Theme, fade time.Duration) {
+	old, from := m.Theme, m.currentTheme()
+	m.Theme = t
+	m.fade = nil
+	m.scaled = nil
+	if fade > 0 && from != nil && t != nil {
+		m.fade = &themeFade{from: from, start: time.Now(), duration: fade}
+		m.stepFade()
+	}
+	m.notifyTheme(old, t)
+	if !sameMetrics(old, t) && m.size != (Size{}) {
+		m.Layout(m.size)
+	}
+}
+
+// notifyTheme tells every widget that the theme changed from old to t.
+func (m *Master) notifyTheme(old, t *Theme) {
+	for _, root := range m.roots() {
+		Walk(root, func(w Widget) bool {
+			if c, ok := w.(interface{ clearTheme() }); ok {
+				c.clearTheme()
+			}
+			return true
+		})
+	}
+	e := ThemeEvent{Old: old, New: t}
+	for _, root := range m.roots() {
+		Walk(root, func(w Widget) bool {
+			ctx := &EventContext{
+				Event:   e,
+				Target:  w,
+				Current: w,
+				Phase:   PhaseTarget,
+				master:  m,
+			}
+			handlersOf(w).run(ctx, true)
+			handlersOf(w).run(ctx, false)
+			if l, ok := w.(ThemeListener); ok {
+				l.ThemeChanged()
+			}
+			return true
+		})
+	}
+}
+
+// Fading returns true while SetTheme is fading between themes.
+func (m *Master) Fading() bool {
+	return m.fade != nil
+}
+
+// Animating returns true while a theme fade or a widget implementing Animator
+// is in progress. Hosts that only draw when there is input should keep
+// drawing frames while it returns true.
+func (m *Master) Animating() bool {
+	if m.Fading() {
+		return true
+	}
+	animating := false
+	m.walkAnimators(func(a Animator) {
+		animating = true
+	})
+	return animating
+}
+
+// stepAnimations advances the animations of all shown widgets for the frame
+// being drawn.
+func (m *Master) stepAnimations() {
+	m.walkAnimators(Animator.StepAnimation)
+}
+
+// walkAnimators calls fn for each shown widget whose animation is running.
+func (m *Master) walkAnimators(fn func(Animator)) {
+	for _, r := range m.roots() {
+		Walk(r, func(w Widget) bool {
+			if isHidden(w) {
+				return false
+			}
+			if a, ok := w.(Animator); ok && a.Animating() {
+				fn(a)
+			}
+			return true
+		})
+	}
+}
+
+// currentTheme returns the theme being shown at the scale of m, which is a
+// blend of two themes during a fade.
+func (m *Master) currentTheme() *Theme {
+	if m.fade != nil {
+		return m.scaledTheme(m.fade.current)
+	}
+	return m.scaledTheme(m.Theme)
+}
+
+// stepFade updates the blend of a fade for the frame being drawn. The blend
+// has the metrics of the new theme from the start.
+func (m *Master) stepFade() {
+	f := m.fade
+	if f == nil {
+		return
+	}
+	p := float64(time.Since(f.start)) / float64(f.duration)
+	if p >= 1 || m.Theme == nil {
+		m.fade = nil
+		return
+	}
+	ret := *m.Theme
+	blendColors(reflect.ValueOf(&ret).Elem(), reflect.ValueOf(f.from).Elem(), p)
+	f.current = &ret
+}
+
+// blendColors blends the colors in to from the colors in from by p, from 0
+// for from to 1 for to. Colors that are not set in both are left as they are.
+func blendColors(to, from reflect.Value, p float64) {
+	switch {
+	case to.Type() == colorType:
+		a, b := from.Interface().(q2d.Color), to.Interface().(q2d.Color)
+		if a == (q2d.Color{}) || b == (q2d.Color{}) {
+			return
+		}
+		to.Set(reflect.ValueOf(lerpColor(a, b, p)))
+	case to.Kind() == reflect.Struct:
+		for i := 0; i < to.NumField(); i++ {
+			if to.Field(i).CanSet() {
+				blendColors(to.Field(i), from.Field(i), p)
+			}
+		}
+	}
+}
+
+// sameMetrics returns true if laying out with a or b gives the same sizes.
+// Border widths are compared as they set the frames of windows.
+func sameMetrics(a, b *Theme) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Font != b.Font || a.Spacing != b.Spacing || a.Padding != b.Padding || a.BorderWidth != b.BorderWidth {
+		return false
+	}
+	for k := range styleKindKeys {
+		sa, sb := a.Styles.kind(StyleKind(k)), b.Styles.kind(StyleKind(k))
+		for _, s := range styleStates {
+			if sa.style(s).Padding != sb.style(s).Padding || sa.style(s).BorderWidth != sb.style(s).BorderWidth {
+				return false
+			}
+		}
+	}
+	return true
+}
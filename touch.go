@@ -0,0 +1,251 @@
+package qui
+
+import (
+	"math"
+	"time"
+
+	"github.com/qbradq/q2d"
+)
+
+// TapDistance is how far in pixels a finger may move before a touch becomes a
+// pan instead of a tap.
+var TapDistance = 10
+
+// TapTime is the longest a finger may stay down for a tap.
+var TapTime = 400 * time.Millisecond
+
+// SwipeVelocity is the least speed in pixels per second a pan has to end with
+// to count as a swipe.
+var SwipeVelocity = 800.0
+
+type gestureKind int
+
+const (
+	gestureNone gestureKind = iota
+	gesturePan
+	gesturePinch
+	// Done with the gesture, waiting for all fingers to lift
+	gestureDone
+)
+
+// touchPoint is a finger on the screen.
+type touchPoint struct {
+	id         int
+	start, pos q2d.Point
+	startTime  time.Time
+	time       time.Time // Of the last move
+	vx, vy     float64   // Smoothed velocity in pixels per second
+}
+
+// touchState is the state of gesture recognition.
+type touchState struct {
+	points  []*touchPoint // In the order the fingers touched down
+	target  Widget        // Widget under the first finger
+	gesture gestureKind
+	tap     bool      // The touch can still become a tap
+	center  q2d.Point // Last center of a pinch
+	dist    float64   // Last distance between the fingers of a pinch
+}
+
+func (s *touchState) find(id int) *touchPoint {
+	for _, p := range s.points {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *touchState) remove(id int) {
+	for i, p := range s.points {
+		if p.id == id {
+			s.points = append(s.points[:i], s.points[i+1:]...)
+			return
+		}
+	}
+}
+
+// pinch returns the center of and distance between the first two fingers.
+func (s *touchState) pinch() (q2d.Point, float64) {
+	a, b := s.points[0].pos, s.points[1].pos
+	d := b.Sub(a)
+	return q2d.Point{(a.X() + b.X()) / 2, (a.Y() + b.Y()) / 2}, math.Hypot(float64(d.X()), float64(d.Y()))
+}
+
+// touchEvent dispatches a touch event to the widget under the first finger
+// and feeds it to gesture recognition.
+func (m *Master) touchEvent(e TouchEvent) bool {
+	s := &m.touch
+	if e.TypeVal == EventTouchBegin && len(s.points) == 0 {
+		s.target = m.widgetAt(e.Pos)
+		s.gesture = gestureNone
+		s.tap = true
+	}
+	target := s.target
+	if !interactive(target) {
+		target = nil
+	}
+	handled := m.dispatch(target, e)
+	if !handled && target != nil {
+		handled = m.deliver(target, e)
+	}
+
+	now := time.Now()
+	switch e.TypeVal {
+	case EventTouchBegin:
+		s.points = append(s.points, &touchPoint{
+			id:        e.ID,
+			start:     e.Pos,
+			pos:       e.Pos,
+			startTime: now,
+			time:      now,
+		})
+		m.touchBegin()
+	case EventTouchMove:
+		p := s.find(e.ID)
+		if p == nil {
+			break
+		}
+		if dt := now.Sub(p.time).Seconds(); dt > 0 {
+			d := e.Pos.Sub(p.pos)
+			p.vx = 0.7*float64(d.X())/dt + 0.3*p.vx
+			p.vy = 0.7*float64(d.Y())/dt + 0.3*p.vy
+		}
+		prev := p.pos
+		p.pos = e.Pos
+		p.time = now
+		m.touchMove(p, prev)
+	case EventTouchEnd, EventTouchCancel:
+		p := s.find(e.ID)
+		if p == nil {
+			break
+		}
+		s.remove(e.ID)
+		// A finger resting before it lifts has no speed
+		if now.Sub(p.time) > 100*time.Millisecond {
+			p.vx, p.vy = 0, 0
+		}
+		m.touchEnd(p, e.TypeVal == EventTouchCancel)
+	}
+	return handled
+}
+
+func (m *Master) touchBegin() {
+	s := &m.touch
+	if len(s.points) != 2 {
+		if len(s.points) > 2 {
+			s.tap = false
+		}
+		return
+	}
+	// A second finger turns the touch into a pinch
+	s.tap = false
+	if s.gesture == gesturePan {
+		m.sendGesture(GestureEvent{TypeVal: EventPan, State: GestureEnded, Pos: s.points[0].pos})
+	}
+	if s.gesture == gestureNone || s.gesture == gesturePan {
+		s.gesture = gesturePinch
+		s.center, s.dist = s.pinch()
+		m.sendGesture(GestureEvent{TypeVal: EventPinch, State: GestureBegan, Pos: s.center, Scale: 1})
+	}
+}
+
+func (m *Master) touchMove(p *touchPoint, prev q2d.Point) {
+	s := &m.touch
+	switch s.gesture {
+	case gestureNone:
+		d := p.pos.Sub(p.start)
+		if dist := m.px(TapDistance); abs(d.X()) <= dist && abs(d.Y()) <= dist {
+			return
+		}
+		s.tap = false
+		s.gesture = gesturePan
+		m.sendGesture(GestureEvent{TypeVal: EventPan, State: GestureBegan, Pos: p.pos, Delta: d})
+	case gesturePan:
+		m.sendGesture(GestureEvent{TypeVal: EventPan, State: GestureChanged, Pos: p.pos, Delta: p.pos.Sub(prev)})
+	case gesturePinch:
+		center, dist := s.pinch()
+		scale := 1.0
+		if s.dist > 0 {
+			scale = dist / s.dist
+		}
+		delta := center.Sub(s.center)
+		s.center, s.dist = center, dist
+		m.sendGesture(GestureEvent{TypeVal: EventPinch, State: GestureChanged, Pos: center, Delta: delta, Scale: scale})
+	}
+}
+
+func (m *Master) touchEnd(p *touchPoint, cancelled bool) {
+	s := &m.touch
+	end := GestureEnded
+	if cancelled {
+		end = GestureCancelled
+	}
+	switch s.gesture {
+	case gestureNone:
+		if s.tap && len(s.points) == 0 && !cancelled && time.Since(p.startTime) <= TapTime {
+			m.tap(p.pos)
+		}
+	case gesturePan:
+		s.gesture = gestureDone
+		m.sendGesture(GestureEvent{TypeVal: EventPan, State: end, Pos: p.pos, VelocityX: p.vx, VelocityY: p.vy})
+		if speed := math.Hypot(p.vx, p.vy); !cancelled && speed >= SwipeVelocity*m.Scale() {
+			g := GestureEvent{TypeVal: EventSwipe, State: GestureEnded, Pos: p.pos, Delta: p.pos.Sub(p.start), VelocityX: p.vx, VelocityY: p.vy}
+			switch {
+			case math.Abs(p.vx) >= math.Abs(p.vy) && p.vx < 0:
+				g.Direction = SwipeLeft
+			case math.Abs(p.vx) >= math.Abs(p.vy):
+				g.Direction = SwipeRight
+			case p.vy < 0:
+				g.Direction = SwipeUp
+			default:
+				g.Direction = SwipeDown
+			}
+			m.sendGesture(g)
+		}
+	case gesturePinch:
+		if len(s.points) < 2 {
+			s.gesture = gestureDone
+			m.sendGesture(GestureEvent{TypeVal: EventPinch, State: end, Pos: s.center, Scale: 1})
+		}
+	}
+	if len(s.points) == 0 {
+		s.target = nil
+		s.gesture = gestureNone
+	}
+}
+
+// tap sends EventTap followed by a left click at pos for widgets that know
+// nothing of touch, unless a handler prevented it.
+func (m *Master) tap(pos q2d.Point) {
+	target := m.touch.target
+	if interactive(target) && m.dispatch(target, GestureEvent{TypeVal: EventTap, State: GestureEnded, Pos: pos}) {
+		return
+	}
+	m.Event(MouseEvent{TypeVal: EventMouseMove, Pos: pos})
+	m.Event(MouseEvent{TypeVal: EventMouseDown, Pos: pos, Button: MouseButtonLeft})
+	m.Event(MouseEvent{TypeVal: EventMouseUp, Pos: pos, Button: MouseButtonLeft})
+}
+
+// sendGesture dispatches g to the widget under the first finger. It returns
+// true if the gesture was consumed.
+func (m *Master) sendGesture(g GestureEvent) bool {
+	target := m.touch.target
+	if !interactive(target) {
+		return false
+	}
+	if m.dispatch(target, g) {
+		return true
+	}
+	return m.deliver(target, g)
+}
+
+// deliver offers e to target and then its ancestors until one consumes it.
+func (m *Master) deliver(target Widget, e Event) bool {
+	for w := target; w != nil; w = parentOf(w) {
+		if w.Event(e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package qui
+
+// adopt makes parent the parent of each non-nil child.
+func adopt(parent Widget, children ...Widget) {
+	for _, c := range children {
+		setParent(c, parent)
+	}
+}
+
+// parentOf returns the parent of w, or nil if w is not a Node.
+func parentOf(w Widget) Widget {
+	if n, ok := w.(Node); ok {
+		return n.GetParent()
+	}
+	return nil
+}
+
+// setParent makes p the parent of w if w is a Node.
+func setParent(w, p Widget) {
+	if n, ok := w.(Node); ok {
+		n.SetParent(p)
+	}
+}
+
+// childrenOf returns the children of w, or nil if w is not a Node.
+func childrenOf(w Widget) []Widget {
+	if n, ok := w.(Node); ok {
+		return n.GetChildren()
+	}
+	return nil
+}
+
+// isDisabled returns true if w is a disabled Node.
+func isDisabled(w Widget) bool {
+	n, ok := w.(Node)
+	return ok && n.IsDisabled()
+}
+
+// isHidden returns true if w is a hidden Node.
+func isHidden(w Widget) bool {
+	n, ok := w.(Node)
+	return ok && n.IsHidden()
+}
+
+// idOf returns the ID of w, or "" if w is not a Node.
+func idOf(w Widget) string {
+	if n, ok := w.(Node); ok {
+		return n.GetID()
+	}
+	return ""
+}
+
+// hasClass returns true if w is a Node with the style class c.
+func hasClass(w Widget, c string) bool {
+	n, ok := w.(Node)
+	return ok && n.HasClass(c)
+}
+
+// handlersOf returns the event handlers of w, or nil if w is not a Node.
+func handlersOf(w Widget) *EventHandlers {
+	if n, ok := w.(Node); ok {
+		return n.Handlers()
+	}
+	return nil
+}
+
+// Walk calls fn for w and all of its descendants, parents before their
+// children. Returning false from fn skips the children of that widget.
+func Walk(w Widget, fn func(Widget) bool) {
+	if w == nil || !fn(w) {
+		return
+	}
+	for _, c := range childrenOf(w) {
+		Walk(c, fn)
+	}
+}
+
+// FindWidget returns the first widget in the tree rooted at w, in the order of
+// Walk, for which pred returns true, or nil.
+func FindWidget(w Widget, pred func(Widget) bool) Widget {
+	var ret Widget
+	Walk(w, func(c Widget) bool {
+		if ret != nil {
+			return false
+		}
+		if pred(c) {
+			ret = c
+			return false
+		}
+		return true
+	})
+	return ret
+}
+
+// FindWidgets returns all widgets in the tree rooted at w for which pred
+// returns true, in the order of Walk.
+func FindWidgets(w Widget, pred func(Widget) bool) []Widget {
+	var ret []Widget
+	Walk(w, func(c Widget) bool {
+		if pred(c) {
+			ret = append(ret, c)
+		}
+		return true
+	})
+	return ret
+}
+
+// Ancestors returns the parent of w, its parent and so on up to the root.
+func Ancestors(w Widget) []Widget {
+	var ret []Widget
+	for p := parentOf(w); p != nil; p = parentOf(p) {
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// IsDescendant returns true if w is ancestor or is contained in it.
+func IsDescendant(w, ancestor Widget) bool {
+	for ; w != nil; w = parentOf(w) {
+		if w == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,9 @@ import (
 	"golang.org/x/image/font"
 )
 
+// windowFrameWidth is the width of the frame of windows before scaling.
+const windowFrameWidth = 2
+
 type Window struct {
 	BaseWidget
 	Title      string
@@ -12,10 +15,21 @@ type Window struct {
 	ShowHeader bool
 	ShowFrame  bool
 	Closable   bool
-	OnClose    func()
+	// Modeless windows are not closed by clicks outside of them.
+	Modeless bool
+	// Maximizable windows fill the screen when their header is double-clicked
+	// and return to their previous place on the next double-click.
+	Maximizable bool
+	OnClose     func()
+	// OnDrag and OnDragEnd are called with the pointer position while the
+	// window is being dragged by its header and when the drag ends.
+	OnDrag    func(pos q2d.Point)
+	OnDragEnd func(pos q2d.Point)
 
 	dragging  bool
 	dragStart q2d.Point
+	maximized bool
+	restore   q2d.Rectangle // Rect before maximizing
 
 	closeBtn *Button
 
@@ -33,44 +47,47 @@ func NewWindow(title string, content Widget) *Window {
 
 	w.closeBtn = NewButton("", func() {
 		if w.overlayManager != nil {
-			w.overlayManager.PopOverlay()
+			removeOverlay(w.overlayManager, w)
 		} else if w.OnClose != nil {
 			// Fallback if not managed
 			w.OnClose()
 		}
 	})
 	w.closeBtn.Icon = IconClose
+	adopt(w, content, w.closeBtn)
 
 	return w
 }
 
+func (w *Window) GetChildren() []Widget {
+	if w.Content == nil {
+		return nil
+	}
+	return []Widget{w.Content}
+}
+
 func (w *Window) MinSize() Size {
 	var contentSz Size
-	if w.Content != nil {
+	if shown(w.Content) {
 		contentSz = w.Content.MinSize()
 	}
 	width := contentSz.Width
 	height := contentSz.Height
 
-	if w.ShowFrame {
-		width += 4 // 2px each side
-		height += 4
-	}
+	theme := w.GetTheme()
+	frame := w.frameWidth(theme)
+	width += 2 * frame
+	height += 2 * frame
 
 	if w.ShowHeader {
-		theme := w.GetTheme()
 		if theme != nil && theme.Font != nil {
-			metrics := theme.Font.Metrics()
-			headerH := (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-			if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerH {
-				headerH = IconSize + theme.Padding.Top + theme.Padding.Bottom
-			}
-			height += headerH
+			height += w.headerHeight(theme)
 
 			// Min width for title + close btn
-			titleW := font.MeasureString(theme.Font, w.Title).Ceil() + theme.Padding.Left + theme.Padding.Right
+			pad := theme.Style(StyleHeader, 0).Padding
+			titleW := font.MeasureString(theme.Font, w.Title).Ceil() + pad.Left + pad.Right
 			if w.Closable {
-				titleW += IconSize + theme.Padding.Left + theme.Padding.Right // Close btn size approx
+				titleW += theme.IconSize() + pad.Left + pad.Right // Close btn size approx
 			}
 			if titleW > width {
 				width = titleW
@@ -90,26 +107,19 @@ func (w *Window) Layout(available Size) Size {
 	// We assume w.Rect is already set by parent or self.
 
 	contentRect := w.Rect
+	theme := w.GetTheme()
 
-	if w.ShowFrame {
+	if frame := w.frameWidth(theme); frame > 0 {
 		contentRect = q2d.Rectangle{
-			contentRect.X() + 2,
-			contentRect.Y() + 2,
-			contentRect.Width() - 4,
-			contentRect.Height() - 4,
+			contentRect.X() + frame,
+			contentRect.Y() + frame,
+			contentRect.Width() - 2*frame,
+			contentRect.Height() - 2*frame,
 		}
 	}
 
 	if w.ShowHeader {
-		headerH := 0
-		theme := w.GetTheme()
-		if theme != nil && theme.Font != nil {
-			metrics := theme.Font.Metrics()
-			headerH = (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-			if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerH {
-				headerH = IconSize + theme.Padding.Top + theme.Padding.Bottom
-			}
-		}
+		headerH := w.headerHeight(theme)
 
 		// Layout Close Button
 		if w.Closable {
@@ -151,7 +161,8 @@ func (w *Window) Layout(available Size) Size {
 		}
 	}
 
-	if w.Content != nil {
+	adopt(w, w.Content)
+	if shown(w.Content) {
 		w.Content.SetRect(contentRect)
 		w.Content.Layout(Size{contentRect.Width(), contentRect.Height()})
 	}
@@ -159,6 +170,24 @@ func (w *Window) Layout(available Size) Size {
 	return Size{w.Rect.Width(), w.Rect.Height()}
 }
 
+// frameWidth returns the width of the frame, or 0 without one.
+func (w *Window) frameWidth(theme *Theme) int {
+	if !w.ShowFrame || theme == nil {
+		return 0
+	}
+	return max(theme.Style(StyleWindow, w.state()).BorderWidth, 0)
+}
+
+// headerHeight returns the height of the title bar.
+func (w *Window) headerHeight(theme *Theme) int {
+	if theme == nil || theme.Font == nil {
+		return 0
+	}
+	pad := theme.Style(StyleHeader, 0).Padding
+	metrics := theme.Font.Metrics()
+	return max((metrics.Ascent+metrics.Descent).Ceil(), theme.IconSize()) + pad.Top + pad.Bottom
+}
+
 func (w *Window) Event(evt Event) bool {
 	// Handle dragging
 	switch event := evt.(type) {
@@ -166,6 +195,9 @@ func (w *Window) Event(evt Event) bool {
 		if w.dragging {
 			if event.TypeVal == EventMouseUp {
 				w.dragging = false
+				if w.OnDragEnd != nil {
+					w.OnDragEnd(event.Pos)
+				}
 				return true
 			}
 			if event.TypeVal == EventMouseMove {
@@ -179,6 +211,9 @@ func (w *Window) Event(evt Event) bool {
 				w.dragStart = event.Pos
 				// Need to re-layout children because absolute positions changed
 				w.Layout(Size{w.Rect.Width(), w.Rect.Height()})
+				if w.OnDrag != nil {
+					w.OnDrag(event.Pos)
+				}
 				return true
 			}
 		}
@@ -186,20 +221,10 @@ func (w *Window) Event(evt Event) bool {
 		if w.Rect.Contains(event.Pos) {
 			// Check header for drag
 			if w.ShowHeader {
-				headerH := 0
 				theme := w.GetTheme()
-				if theme != nil && theme.Font != nil {
-					metrics := theme.Font.Metrics()
-					headerH = (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-					if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerH {
-						headerH = IconSize + theme.Padding.Top + theme.Padding.Bottom
-					}
-				}
+				headerH := w.headerHeight(theme)
 
-				frameOffset := 0
-				if w.ShowFrame {
-					frameOffset = 2
-				}
+				frameOffset := w.frameWidth(theme)
 
 				headerRect := q2d.Rectangle{
 					w.Rect.X() + frameOffset,
@@ -209,12 +234,16 @@ func (w *Window) Event(evt Event) bool {
 				}
 
 				if headerRect.Contains(event.Pos) {
-					// Check close button
-					if w.Closable && w.closeBtn.Event(evt) {
+					if event.TypeVal == EventMouseDown && event.Clicks == 2 && w.Maximizable {
+						if w.maximized {
+							w.Restore()
+						} else {
+							w.Maximize()
+						}
 						return true
 					}
 
-					if event.TypeVal == EventMouseDown {
+					if event.TypeVal == EventMouseDown && !w.maximized {
 						w.dragging = true
 						w.dragStart = event.Pos
 						return true
@@ -222,12 +251,7 @@ func (w *Window) Event(evt Event) bool {
 				}
 			}
 
-			// Pass to content
-			if w.Content != nil {
-				if w.Content.Event(evt) {
-					return true
-				}
-			}
+			// Events that the content does not consume stop at the window
 			return true
 		}
 	}
@@ -235,20 +259,54 @@ func (w *Window) Event(evt Event) bool {
 	return false
 }
 
+// Maximize makes the window fill the screen of its overlay manager. Windows
+// are only maximized by overlay managers that implement OverlayStack.
+func (w *Window) Maximize() {
+	if w.maximized || w.overlayManager == nil {
+		return
+	}
+	sz := screenSize(w.overlayManager)
+	if sz == (Size{}) {
+		return
+	}
+	w.maximized = true
+	w.restore = w.Rect
+	w.Rect = q2d.Rectangle{0, 0, sz.Width, sz.Height}
+	w.Layout(sz)
+}
+
+// Restore returns a maximized window to where it was before.
+func (w *Window) Restore() {
+	if !w.maximized {
+		return
+	}
+	w.maximized = false
+	w.Rect = w.restore
+	w.Layout(Size{w.Rect.Width(), w.Rect.Height()})
+}
+
+func (w *Window) IsMaximized() bool {
+	return w.maximized
+}
+
+func (w *Window) GrabsPointer() bool {
+	return w.dragging
+}
+
 func (w *Window) FindWidgetAt(pos q2d.Point) Widget {
 	if !w.Rect.Contains(pos) {
 		return nil
 	}
 
 	// Check Close Button
-	if w.ShowHeader && w.closeBtn != nil {
+	if w.ShowHeader && w.Closable {
 		if found := w.closeBtn.FindWidgetAt(pos); found != nil {
 			return found
 		}
 	}
 
 	// Check Content
-	if w.Content != nil {
+	if shown(w.Content) {
 		if found := w.Content.FindWidgetAt(pos); found != nil {
 			return found
 		}
@@ -263,53 +321,41 @@ func (w *Window) Draw(img *q2d.Image) {
 		return
 	}
 
+	st := theme.Style(StyleWindow, w.state())
+	if w.overlayManager != nil {
+		DrawShadow(img, w.Rect, st.Radius, theme.Shadow)
+	}
+
 	// Draw Frame
-	if w.ShowFrame {
-		img.PushSubImage(w.Rect)
-		img.Fill(theme.BorderColor) // Frame color
-		// Fill inside with background
-		inner := q2d.Rectangle{2, 2, w.Rect.Width() - 4, w.Rect.Height() - 4}
-		img.PushSubImage(inner)
-		img.Fill(theme.BackgroundColor)
-		img.PopSubImage()
-		img.PopSubImage()
-	} else {
-		img.PushSubImage(w.Rect)
-		img.Fill(theme.BackgroundColor)
-		img.PopSubImage()
+	frame := w.frameWidth(theme)
+	if frame == 0 {
+		st.BorderWidth = -1
 	}
+	img.PushSubImage(w.Rect)
+	DrawBox(img, q2d.Rectangle{0, 0, w.Rect.Width(), w.Rect.Height()}, st)
+	img.PopSubImage()
 
 	// Draw Header
-	if w.ShowHeader {
-		headerH := 0
-		if theme.Font != nil {
-			metrics := theme.Font.Metrics()
-			headerH = (metrics.Ascent + metrics.Descent).Ceil() + theme.Padding.Top + theme.Padding.Bottom
-			if IconSize+theme.Padding.Top+theme.Padding.Bottom > headerH {
-				headerH = IconSize + theme.Padding.Top + theme.Padding.Bottom
-			}
-		}
-
-		frameOffset := 0
-		if w.ShowFrame {
-			frameOffset = 2
-		}
-
+	if w.ShowHeader && theme.Font != nil {
+		headerH := w.headerHeight(theme)
 		headerRect := q2d.Rectangle{
-			w.Rect.X() + frameOffset,
-			w.Rect.Y() + frameOffset,
-			w.Rect.Width() - frameOffset*2,
+			w.Rect.X() + frame,
+			w.Rect.Y() + frame,
+			w.Rect.Width() - frame*2,
 			headerH,
 		}
 
+		// The top corners follow the inside of the frame
+		hst := theme.Style(StyleHeader, w.state())
+		hst.Radius = max(hst.Radius-frame, 0)
 		img.PushSubImage(headerRect)
-		img.Fill(theme.PrimaryColor) // Header color
+		drawBox(img, q2d.Rectangle{0, 0, headerRect.Width(), headerH}, hst, cornersTop)
 
 		// Title
 		metrics := theme.Font.Metrics()
 		textHeight := (metrics.Ascent + metrics.Descent).Ceil()
 		textY := (headerH - textHeight) / 2
-		img.Text(q2d.Point{theme.Padding.Left, textY}, theme.TextColor, theme.Font, false, "%s", w.Title)
+		img.Text(q2d.Point{hst.Padding.Left, textY}, hst.Text, theme.Font, false, "%s", w.Title)
 
 		img.PopSubImage()
 
@@ -318,7 +364,7 @@ func (w *Window) Draw(img *q2d.Image) {
 		}
 	}
 
-	if w.Content != nil {
+	if shown(w.Content) {
 		w.Content.Draw(img)
 	}
 }
@@ -327,6 +373,10 @@ func (w *Window) SetOverlayManager(m OverlayManager) {
 	w.overlayManager = m
 }
 
+func (w *Window) IsModeless() bool {
+	return w.Modeless
+}
+
 func (w *Window) OnDismiss() {
 	if w.OnClose != nil {
 		w.OnClose()